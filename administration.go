@@ -1 +1,39 @@
 package exact
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tim-online/go-exactonline/rest"
+	"github.com/tim-online/go-exactonline/system"
+)
+
+// Division is an administration the authenticated user has access to
+type Division = system.DivisionsUser
+
+// Divisions returns all divisions of /system/Divisions, following the pages
+// of the response. Use the Code of a division with SetDivisionID to switch
+// administrations. When no division is set the current division of the user
+// is used for the request.
+func (c *Client) Divisions(ctx context.Context) ([]Division, error) {
+	division := c.Division()
+	if division == 0 {
+		var err error
+		division, err = c.CurrentDivision(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := c.NewRequest(ctx, http.MethodGet, system.DivisionsEndpoint, nil, rest.ForDivision(division))
+	if err != nil {
+		return nil, err
+	}
+
+	divisions := []Division{}
+	_, err = c.DoAll(req, &divisions)
+	if err != nil {
+		return nil, err
+	}
+	return divisions, nil
+}
@@ -21,15 +21,12 @@ import (
 
 const (
 	DefaultBaseURL = "https://start.exactonline.nl/api"
-
-	libraryVersion = "0.0.1"
-	userAgent      = "go-exactonline/" + libraryVersion
 )
 
 // Client manages communication with Exact Online API
 type Client struct {
 	// REST client used to communicate with the API.
-	rest.Client
+	*rest.Client
 
 	// Services
 	// Accountancy          *Accountancy
@@ -66,35 +63,42 @@ type Client struct {
 	// Workflow             *Workflow
 }
 
-// NewClient returns a new Exact Online API client
+// NewClient returns a new Exact Online API client for division. Use 0 to set
+// the division later with SetDivisionID, e.g. with the result of
+// CurrentDivision: requests for a division fail with rest.ErrNoDivision until
+// then. NewClient panics on a negative division.
 func NewClient(httpClient *http.Client, divisionID int) *Client {
 	if httpClient == nil {
-		httpClient = http.DefaultClient
+		httpClient = rest.DefaultHTTPClient()
 	}
 
 	c := &Client{
-		Client: *rest.New(httpClient),
+		Client: rest.New(httpClient),
 	}
 
 	// set default options
 	baseURL, _ := url.Parse(DefaultBaseURL)
 	c.SetBaseURL(baseURL)
-	c.SetDivisionID(divisionID)
-	c.SetUserAgent(userAgent)
+	if divisionID != 0 {
+		err := c.SetDivisionID(divisionID)
+		if err != nil {
+			panic(err)
+		}
+	}
 	c.SetDebug(false)
 
-	c.CRM = crm.NewService(&c.Client)
-	c.Financial = financial.NewService(&c.Client)
-	c.FinancialTransaction = financialtransaction.NewService(&c.Client)
-	c.General = general.NewService(&c.Client)
-	c.GeneralJournalEntry = generaljournalentry.NewService(&c.Client)
-	c.HRM = hrm.NewService(&c.Client)
-	c.Logistics = logistics.NewService(&c.Client)
-	c.SalesEntry = salesentry.NewService(&c.Client)
-	c.SalesInvoice = salesinvoice.NewService(&c.Client)
-	c.SalesOrder = salesorder.NewService(&c.Client)
-	c.System = system.NewService(&c.Client)
-	c.VAT = vat.NewService(&c.Client)
+	c.CRM = crm.NewService(c.Client)
+	c.Financial = financial.NewService(c.Client)
+	c.FinancialTransaction = financialtransaction.NewService(c.Client)
+	c.General = general.NewService(c.Client)
+	c.GeneralJournalEntry = generaljournalentry.NewService(c.Client)
+	c.HRM = hrm.NewService(c.Client)
+	c.Logistics = logistics.NewService(c.Client)
+	c.SalesEntry = salesentry.NewService(c.Client)
+	c.SalesInvoice = salesinvoice.NewService(c.Client)
+	c.SalesOrder = salesorder.NewService(c.Client)
+	c.System = system.NewService(c.Client)
+	c.VAT = vat.NewService(c.Client)
 
 	return c
 }
@@ -103,12 +107,12 @@ func (c *Client) SetDebug(debug bool) {
 	c.Client.SetDebug(debug)
 }
 
-func (c *Client) SetBaseURL(baseURL *url.URL) {
+func (c *Client) SetBaseURL(baseURL *url.URL) error {
 	// set base url for use in http client
-	c.Client.SetBaseURL(baseURL)
+	return c.Client.SetBaseURL(baseURL)
 }
 
-func (c *Client) SetDivisionID(divisionID int) {
-	// set base url for use in http client
-	c.Client.SetDivisionID(divisionID)
+func (c *Client) SetDivisionID(divisionID int) error {
+	// set division for use in http client
+	return c.Client.SetDivisionID(divisionID)
 }
@@ -19,7 +19,7 @@ const (
 func (s *Service) AccountsGet(requestParams *AccountsGetParams, ctx context.Context) (*AccountsGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewAccountsGetResponse()
-	path := s.rest.SubPath(AccountsEndpoint)
+	path := AccountsEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
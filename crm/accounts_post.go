@@ -10,7 +10,7 @@ import (
 func (s *Service) AccountsPost(body *AccountsPostBody, ctx context.Context) (*AccountsPostResponse, error) {
 	method := http.MethodPost
 	responseBody := s.NewAccountsPostResponse()
-	path := s.rest.SubPath(AccountsEndpoint)
+	path := AccountsEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, body)
@@ -3,15 +3,17 @@ package crm
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/tim-online/go-exactonline/edm"
+	"github.com/tim-online/go-exactonline/rest"
 )
 
 // POST
 
 func (s *Service) AccountsPut(body *AccountsPutBody, ctx context.Context) error {
 	method := http.MethodPut
-	path := s.rest.SubPathWithID(AccountsEndpoint, body.ID.String())
+	path := rest.EntityPath(strings.TrimSuffix(AccountsEndpoint, "{id}"), body.ID)
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, body)
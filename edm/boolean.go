@@ -1,3 +1,29 @@
 package edm
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 type Boolean bool
+
+func (b Boolean) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bool(b))
+}
+
+// UnmarshalJSON accepts true/false, "true"/"false" and 1/0. Null is decoded
+// as false.
+func (b *Boolean) UnmarshalJSON(text []byte) (err error) {
+	value := strings.ToLower(strings.Trim(strings.TrimSpace(string(text)), `"`))
+
+	switch value {
+	case "true", "1":
+		*b = true
+	case "false", "0", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("Invalid boolean %s", text)
+	}
+	return nil
+}
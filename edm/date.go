@@ -0,0 +1,66 @@
+package edm
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Date is a date without a time component. Exact Online sends dates as
+// /Date(1488931200000)/ at midnight UTC: the time is always truncated to the
+// calendar day in UTC so formatting doesn't depend on the local timezone.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date at midnight UTC
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.Time.IsZero() {
+		return json.Marshal(nil)
+	}
+
+	t := truncateDate(d.Time)
+	return json.Marshal(fmt.Sprintf("/Date(%d)/", t.UnixMilli()))
+}
+
+func (d Date) IsEmpty() bool {
+	return d.Time.IsZero()
+}
+
+func (d *Date) UnmarshalJSON(text []byte) (err error) {
+	var value string
+	err = json.Unmarshal(text, &value)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	// 2017-03-08
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t, err = parseDateTime(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.IsZero() {
+		return nil
+	}
+
+	d.Time = truncateDate(t)
+	return nil
+}
+
+// truncateDate returns midnight UTC of the calendar day of t in UTC
+func truncateDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -2,21 +2,29 @@ package edm
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strconv"
 	"time"
 )
 
+// DateTimeLocation is the location decoded DateTime values are set to. It
+// defaults to UTC so decoding doesn't depend on the timezone of the machine.
+var DateTimeLocation = time.UTC
+
 type DateTime struct {
 	time.Time
 }
 
-func (d *DateTime) MarshalJSON() ([]byte, error) {
+// MarshalJSON writes the time in the /Date(1488939627017)/ notation that Exact
+// Online expects. A zero time is written as null.
+func (d DateTime) MarshalJSON() ([]byte, error) {
 	if d.Time.IsZero() {
 		return json.Marshal(nil)
 	}
 
-	return json.Marshal(d.Time)
+	// UnixNano overflows outside the years 1678-2262
+	return json.Marshal(fmt.Sprintf("/Date(%d)/", d.Time.UnixMilli()))
 }
 
 func (d DateTime) IsEmpty() bool {
@@ -34,25 +42,33 @@ func (d *DateTime) UnmarshalJSON(text []byte) (err error) {
 		return nil
 	}
 
+	d.Time, err = parseDateTime(value)
+	return err
+}
+
+// dateTimeRe matches /Date(1488939627017)/, optionally with an offset that's
+// ignored as the milliseconds are in UTC
+var dateTimeRe = regexp.MustCompile(`^/Date\((-?[0-9]+)([+-][0-9]{4})?\)/$`)
+
+// parseDateTime parses RFC3339 and /Date(1488939627017)/ notations
+func parseDateTime(value string) (time.Time, error) {
 	// first try standard date
-	d.Time, err = time.Parse(time.RFC3339, value)
+	t, err := time.Parse(time.RFC3339, value)
 	if err == nil {
-		return nil
+		return t.In(DateTimeLocation), nil
 	}
 
 	// /Date(1488939627017)/
-	re := regexp.MustCompile(`[0-9]+`)
-	match := re.FindString(value)
-	if match == "" {
-		return nil
+	match := dateTimeRe.FindStringSubmatch(value)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("Invalid date time %q: expected /Date(...)/ or RFC3339", value)
 	}
 
-	milis, err := strconv.Atoi(match)
+	milis, err := strconv.ParseInt(match[1], 10, 64)
 	if err != nil {
-		return err
+		return time.Time{}, err
 	}
 
 	// new Date(milis)
-	d.Time = time.Unix(0, int64(milis)*int64(time.Millisecond))
-	return err
+	return time.UnixMilli(milis).In(DateTimeLocation), nil
 }
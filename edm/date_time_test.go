@@ -0,0 +1,145 @@
+package edm
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	tests := []time.Time{
+		{},
+		time.Date(2017, 3, 8, 2, 20, 27, 17000000, time.UTC),
+		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC),
+		// open ended periods
+		time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC),
+	}
+
+	for _, want := range tests {
+		b, err := json.Marshal(DateTime{Time: want})
+		if err != nil {
+			t.Errorf("Marshal(%s) returned error: %s", want, err)
+			continue
+		}
+
+		got := DateTime{}
+		err = json.Unmarshal(b, &got)
+		if err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %s", b, err)
+			continue
+		}
+
+		if !got.Time.Equal(want) {
+			t.Errorf("Unmarshal(Marshal(%s)) = %s via %s", want, got.Time, b)
+		}
+	}
+}
+
+func TestDateTimeUnmarshal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		err  bool
+	}{
+		{`"/Date(1488939627017)/"`, time.Date(2017, 3, 8, 2, 20, 27, 17000000, time.UTC), false},
+		{`"/Date(1488939627017+0100)/"`, time.Date(2017, 3, 8, 2, 20, 27, 17000000, time.UTC), false},
+		{`"/Date(-62135596800000)/"`, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{`"2017-03-08T02:20:27Z"`, time.Date(2017, 3, 8, 2, 20, 27, 0, time.UTC), false},
+		{`""`, time.Time{}, false},
+		{`"tomorrow"`, time.Time{}, true},
+		{`"8-3-2017"`, time.Time{}, true},
+		{`"/Date(abc)/"`, time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		got := DateTime{}
+		err := json.Unmarshal([]byte(test.in), &got)
+		if (err != nil) != test.err {
+			t.Errorf("Unmarshal(%s) returned error %v, want error %v", test.in, err, test.err)
+			continue
+		}
+		if !got.Time.Equal(test.want) {
+			t.Errorf("Unmarshal(%s) = %s, want %s", test.in, got.Time, test.want)
+		}
+	}
+}
+
+func TestDateTimeMarshal(t *testing.T) {
+	tests := []struct {
+		in   time.Time
+		want string
+	}{
+		{time.Time{}, `null`},
+		{time.Date(2017, 3, 8, 2, 20, 27, 17000000, time.UTC), `"/Date(1488939627017)/"`},
+		{time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), `"/Date(253402214400000)/"`},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(DateTime{Time: test.in})
+		if err != nil {
+			t.Errorf("Marshal(%s) returned error: %s", test.in, err)
+			continue
+		}
+		if string(b) != test.want {
+			t.Errorf("Marshal(%s) = %s, want %s", test.in, b, test.want)
+		}
+	}
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	tests := []Date{
+		{},
+		NewDate(2017, 3, 8),
+		NewDate(1601, 1, 1),
+		NewDate(9999, 12, 31),
+	}
+
+	for _, want := range tests {
+		b, err := json.Marshal(want)
+		if err != nil {
+			t.Errorf("Marshal(%s) returned error: %s", want.Time, err)
+			continue
+		}
+
+		got := Date{}
+		err = json.Unmarshal(b, &got)
+		if err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %s", b, err)
+			continue
+		}
+
+		if !got.Time.Equal(want.Time) {
+			t.Errorf("Unmarshal(Marshal(%s)) = %s via %s", want.Time, got.Time, b)
+		}
+	}
+}
+
+func TestNullDateTimeRoundTrip(t *testing.T) {
+	tests := []NullDateTime{
+		{},
+		NewNullDateTime(time.Date(2017, 3, 8, 2, 20, 27, 17000000, time.UTC)),
+		NewNullDateTime(time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)),
+	}
+
+	for _, want := range tests {
+		b, err := json.Marshal(want)
+		if err != nil {
+			t.Errorf("Marshal(%s) returned error: %s", want.Time, err)
+			continue
+		}
+
+		got := NullDateTime{}
+		err = json.Unmarshal(b, &got)
+		if err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %s", b, err)
+			continue
+		}
+
+		if got.Valid != want.Valid || !got.Time.Equal(want.Time) {
+			t.Errorf("Unmarshal(Marshal(%s)) = %s via %s", want.Time, got.Time, b)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package edm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// -12.345, 5E-05
+var decimalPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// Decimal holds a decimal number in the exact representation Exact Online sent
+// it in. Use it for amounts and quantities that can't afford float64 rounding.
+type Decimal struct {
+	value string
+}
+
+// NewDecimal parses a decimal number like "12.34"
+func NewDecimal(s string) (Decimal, error) {
+	if !decimalPattern.MatchString(s) {
+		return Decimal{}, fmt.Errorf("Invalid decimal \"%s\"", s)
+	}
+	return Decimal{value: s}, nil
+}
+
+// NewDecimalFromFloat converts f to a decimal using the shortest
+// representation that round trips
+func NewDecimalFromFloat(f float64) Decimal {
+	return Decimal{value: strconv.FormatFloat(f, 'f', -1, 64)}
+}
+
+func (d Decimal) IsEmpty() bool {
+	return d.value == ""
+}
+
+func (d Decimal) String() string {
+	if d.IsEmpty() {
+		return "0"
+	}
+	return d.value
+}
+
+// Float64 returns the nearest float64 value of the decimal
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Rat returns the decimal as an exact rational number for calculations
+func (d Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(d.String())
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.IsEmpty() {
+		return json.Marshal(nil)
+	}
+
+	return []byte(d.value), nil
+}
+
+func (d *Decimal) UnmarshalJSON(text []byte) (err error) {
+	// null
+	if string(text) == "null" {
+		d.value = ""
+		return nil
+	}
+
+	// 12.34
+	var n json.Number
+	err = json.Unmarshal(text, &n)
+	if err == nil {
+		*d, err = NewDecimal(n.String())
+		return err
+	}
+
+	// "12.34"
+	var s string
+	err = json.Unmarshal(text, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == "" {
+		d.value = ""
+		return nil
+	}
+
+	*d, err = NewDecimal(s)
+	return err
+}
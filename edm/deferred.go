@@ -0,0 +1,50 @@
+package edm
+
+import (
+	"encoding/json"
+)
+
+// Deferred is a navigation property that wasn't expanded:
+// {"__deferred": {"uri": "https://start.exactonline.nl/api/v1/..."}}
+type Deferred struct {
+	URI string
+}
+
+func (d Deferred) IsEmpty() bool {
+	return d.URI == ""
+}
+
+func (d Deferred) MarshalJSON() ([]byte, error) {
+	if d.IsEmpty() {
+		return json.Marshal(nil)
+	}
+
+	v := deferred{}
+	v.Deferred.URI = d.URI
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON reads the uri of a deferred property. Null and expanded
+// values leave the URI empty.
+func (d *Deferred) UnmarshalJSON(text []byte) error {
+	d.URI = ""
+
+	v := deferred{}
+	err := json.Unmarshal(text, &v)
+	if err != nil {
+		// an expanded collection
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return nil
+		}
+		return err
+	}
+
+	d.URI = v.Deferred.URI
+	return nil
+}
+
+type deferred struct {
+	Deferred struct {
+		URI string `json:"uri"`
+	} `json:"__deferred"`
+}
@@ -0,0 +1,139 @@
+package edm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnumValue is the underlying type of an enum: Exact Online uses both string
+// codes and numbers
+type EnumValue interface {
+	~string | ~int | ~int16 | ~int32 | ~int64 | ~uint8
+}
+
+// Enum validates the values of a custom enum type against a known set. Use it
+// in the UnmarshalJSON and Literal methods of the type:
+//
+//	type Status string
+//
+//	var statuses = edm.NewEnum[Status]("Status", "A", "P", "S")
+//
+//	func (s *Status) UnmarshalJSON(data []byte) error {
+//		return statuses.Unmarshal(data, s)
+//	}
+//
+//	func (s Status) Literal() string {
+//		return statuses.Literal(s)
+//	}
+//
+// The zero value is always accepted as Exact Online returns null or an empty
+// value for properties that aren't set.
+type Enum[T EnumValue] struct {
+	name   string
+	values []T
+	valid  map[T]bool
+}
+
+// NewEnum returns an enum called name, used in errors, with the given values
+func NewEnum[T EnumValue](name string, values ...T) *Enum[T] {
+	e := &Enum[T]{name: name, values: values, valid: map[T]bool{}}
+	for _, v := range values {
+		e.valid[v] = true
+	}
+	return e
+}
+
+// Values returns the known values in the order they were passed to NewEnum
+func (e *Enum[T]) Values() []T {
+	return append([]T{}, e.values...)
+}
+
+// Valid reports if v is one of the known values or the zero value
+func (e *Enum[T]) Valid(v T) bool {
+	var zero T
+	return v == zero || e.valid[v]
+}
+
+// Validate returns an error when v isn't a known value
+func (e *Enum[T]) Validate(v T) error {
+	if e.Valid(v) {
+		return nil
+	}
+
+	// use the literals, a String method may hide the actual value
+	literals := make([]string, len(e.values))
+	for i, value := range e.values {
+		literals[i] = e.Literal(value)
+	}
+	return fmt.Errorf("Invalid %s %s, expected one of %s", e.name, e.Literal(v), strings.Join(literals, ", "))
+}
+
+// Unmarshal decodes data in v and validates the result. Null leaves v at its
+// zero value. Unknown values return an error and leave v untouched.
+func (e *Enum[T]) Unmarshal(data []byte, v *T) error {
+	var value T
+	rv := reflect.ValueOf(&value).Elem()
+
+	// decode in the underlying type, T itself would call UnmarshalJSON again
+	switch rv.Kind() {
+	case reflect.String:
+		var s *string
+		err := json.Unmarshal(data, &s)
+		if err != nil {
+			return err
+		}
+		if s != nil {
+			rv.SetString(*s)
+		}
+	case reflect.Uint8:
+		var n *uint64
+		err := json.Unmarshal(data, &n)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			if rv.OverflowUint(*n) {
+				return fmt.Errorf("Invalid %s %d: out of range", e.name, *n)
+			}
+			rv.SetUint(*n)
+		}
+	default:
+		var n *int64
+		err := json.Unmarshal(data, &n)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			if rv.OverflowInt(*n) {
+				return fmt.Errorf("Invalid %s %d: out of range", e.name, *n)
+			}
+			rv.SetInt(*n)
+		}
+	}
+
+	err := e.Validate(value)
+	if err != nil {
+		return err
+	}
+
+	*v = value
+	return nil
+}
+
+// Literal returns v as an OData literal for $filter expressions: quoted for
+// string enums, with the L suffix for int64 enums
+func (e *Enum[T]) Literal(v T) string {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return EscapeString(rv.String())
+	case reflect.Int64:
+		return Int64Literal(rv.Int())
+	case reflect.Uint8:
+		return strconv.FormatUint(rv.Uint(), 10)
+	}
+	return strconv.FormatInt(rv.Int(), 10)
+}
@@ -0,0 +1,38 @@
+package edm
+
+import "testing"
+
+type priority int16
+
+var priorities = NewEnum[priority]("Priority", 1, 2, 3)
+
+type level uint8
+
+var levels = NewEnum[level]("Level", 1, 2)
+
+func TestEnumOverflow(t *testing.T) {
+	p := priority(2)
+	for _, in := range []string{`65537`, `-32769`} {
+		err := priorities.Unmarshal([]byte(in), &p)
+		if err == nil {
+			t.Errorf("Unmarshal(%s) expected an error", in)
+		}
+		if p != 2 {
+			t.Errorf("Unmarshal(%s) changed the value to %d", in, p)
+		}
+	}
+
+	l := level(1)
+	err := levels.Unmarshal([]byte(`257`), &l)
+	if err == nil {
+		t.Errorf("Unmarshal(257) expected an error")
+	}
+	if l != 1 {
+		t.Errorf("Unmarshal(257) changed the value to %d", l)
+	}
+
+	err = priorities.Unmarshal([]byte(`3`), &p)
+	if err != nil || p != 3 {
+		t.Errorf("Unmarshal(3) got %d, %v", p, err)
+	}
+}
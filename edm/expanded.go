@@ -0,0 +1,67 @@
+package edm
+
+import (
+	"encoding/json"
+
+	"github.com/tim-online/go-exactonline/utils"
+)
+
+// Expanded is a navigation property that can be requested with $expand. It
+// decodes all forms Exact Online uses for the property:
+//
+//	standalone: "SalesInvoiceLines": []
+//	embedded:   "SalesInvoiceLines": {"results": []}
+//	deferred:   "SalesInvoiceLines": {"__deferred": {"uri": "..."}}
+//	empty:      "SalesInvoiceLines": null
+//
+// Results holds the records when the property was expanded, Deferred the uri
+// of the records when it wasn't. A null property leaves both empty.
+type Expanded[T any] struct {
+	Results  []T
+	Deferred Deferred
+}
+
+// IsEmpty reports if the property wasn't expanded and has no records
+func (e Expanded[T]) IsEmpty() bool {
+	return len(e.Results) == 0
+}
+
+// MarshalJSON writes the records as a plain array, the form Exact Online
+// expects when posting an entity with its lines. Nil results are written as
+// null, an empty slice as [].
+func (e Expanded[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Results)
+}
+
+func (e *Expanded[T]) UnmarshalJSON(data []byte) error {
+	e.Results = nil
+	e.Deferred = Deferred{}
+
+	// create the json tester
+	tester := &utils.JsonTester{}
+	err := json.Unmarshal(data, tester)
+	if err != nil {
+		return err
+	}
+
+	// test if json is array (standalone)
+	if tester.IsArray() {
+		return json.Unmarshal(data, &e.Results)
+	}
+
+	// null
+	if !tester.IsObject() {
+		return nil
+	}
+
+	envelope := struct {
+		Results []T `json:"results"`
+	}{}
+	err = json.Unmarshal(data, &envelope)
+	if err != nil {
+		return err
+	}
+	e.Results = envelope.Results
+
+	return e.Deferred.UnmarshalJSON(data)
+}
@@ -0,0 +1,84 @@
+package edm
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type contact struct {
+	ID string
+}
+
+func TestExpandedNull(t *testing.T) {
+	tests := []struct {
+		in       string
+		results  int
+		deferred string
+	}{
+		{`{"Contacts": null}`, 0, ""},
+		{`{"Contacts": {"results": []}}`, 0, ""},
+		{`{"Contacts": {"results": null}}`, 0, ""},
+		{`{"Contacts": {"__deferred": null}}`, 0, ""},
+		{`{"Contacts": [{"ID": "a"}]}`, 1, ""},
+		{`{"Contacts": {"results": [{"ID": "a"}, {"ID": "b"}]}}`, 2, ""},
+		{`{"Contacts": {"__deferred": {"uri": "https://start.exactonline.nl/api/v1/1/crm/Contacts"}}}`, 0, "https://start.exactonline.nl/api/v1/1/crm/Contacts"},
+	}
+
+	for _, test := range tests {
+		v := struct {
+			Contacts Expanded[contact]
+		}{Contacts: Expanded[contact]{Results: []contact{{ID: "old"}}}}
+
+		err := json.Unmarshal([]byte(test.in), &v)
+		if err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %s", test.in, err)
+			continue
+		}
+
+		if len(v.Contacts.Results) != test.results {
+			t.Errorf("Unmarshal(%s) got %d results, want %d", test.in, len(v.Contacts.Results), test.results)
+		}
+		if v.Contacts.Deferred.URI != test.deferred {
+			t.Errorf("Unmarshal(%s) got deferred %q, want %q", test.in, v.Contacts.Deferred.URI, test.deferred)
+		}
+	}
+}
+
+func TestExpandedMarshal(t *testing.T) {
+	tests := []struct {
+		in  Expanded[contact]
+		out string
+	}{
+		{Expanded[contact]{}, `null`},
+		{Expanded[contact]{Results: []contact{}}, `[]`},
+		{Expanded[contact]{Results: []contact{{ID: "a"}}}, `[{"ID":"a"}]`},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(test.in)
+		if err != nil {
+			t.Errorf("Marshal(%v) returned error: %s", test.in.Results, err)
+			continue
+		}
+		if string(b) != test.out {
+			t.Errorf("Marshal(%v) got %s, want %s", test.in.Results, b, test.out)
+		}
+	}
+}
+
+func TestDeferredNull(t *testing.T) {
+	for _, in := range []string{`{"Contacts": null}`, `{"Contacts": {"__deferred": null}}`, `{"Contacts": {"results": []}}`} {
+		v := struct {
+			Contacts Deferred
+		}{Contacts: Deferred{URI: "old"}}
+
+		err := json.Unmarshal([]byte(in), &v)
+		if err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %s", in, err)
+			continue
+		}
+		if !v.Contacts.IsEmpty() {
+			t.Errorf("Unmarshal(%s) got deferred %q, want none", in, v.Contacts.URI)
+		}
+	}
+}
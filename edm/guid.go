@@ -2,15 +2,33 @@ package edm
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
 
 	"github.com/satori/go.uuid"
 )
 
+// 00000000-0000-0000-0000-000000000000
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 // type GUID uuid.UUID
 type GUID struct {
 	uuid.UUID
 }
 
+// ParseGUID parses a GUID in the 00000000-0000-0000-0000-000000000000 form
+func ParseGUID(input string) (GUID, error) {
+	if !guidPattern.MatchString(input) {
+		return GUID{}, fmt.Errorf("Invalid GUID \"%s\": expected 36 character hex form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", input)
+	}
+
+	u, err := uuid.FromString(input)
+	if err != nil {
+		return GUID{}, fmt.Errorf("Invalid GUID \"%s\": %s", input, err)
+	}
+	return GUID{UUID: u}, nil
+}
+
 func (g GUID) IsEmpty() bool {
 	return g.UUID == uuid.Nil
 }
@@ -22,6 +40,11 @@ func (g GUID) String() string {
 	return g.UUID.String()
 }
 
+// Literal returns the GUID in the guid'...' form used in $filter expressions
+func (g GUID) Literal() string {
+	return fmt.Sprintf("guid'%s'", g.UUID.String())
+}
+
 func (g GUID) MarshalJSON() ([]byte, error) {
 	if g.IsEmpty() {
 		return json.Marshal(nil)
@@ -30,6 +53,29 @@ func (g GUID) MarshalJSON() ([]byte, error) {
 	return json.Marshal(g.UUID)
 }
 
-func (g GUID) FromString(input string) {
-	g.UUID, _ = uuid.FromString(input)
+func (g *GUID) UnmarshalJSON(text []byte) (err error) {
+	var value string
+	err = json.Unmarshal(text, &value)
+	if err != nil {
+		return err
+	}
+
+	// null or ""
+	if value == "" {
+		g.UUID = uuid.Nil
+		return nil
+	}
+
+	*g, err = ParseGUID(value)
+	return err
+}
+
+func (g *GUID) FromString(input string) error {
+	guid, err := ParseGUID(input)
+	if err != nil {
+		return err
+	}
+
+	*g = guid
+	return nil
 }
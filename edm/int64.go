@@ -1,3 +1,49 @@
 package edm
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type Int64 int64
+
+// MarshalJSON writes the value as a quoted string like OData does for Edm.Int64
+// so it doesn't lose precision in javascript clients
+func (i Int64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+// UnmarshalJSON accepts a json number or a quoted numeric string. Values that
+// don't fit in 64 bits return an error.
+func (i *Int64) UnmarshalJSON(text []byte) (err error) {
+	value := strings.TrimSpace(string(text))
+	if value == "null" {
+		*i = 0
+		return nil
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		err = json.Unmarshal(text, &value)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			*i = 0
+			return nil
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return fmt.Errorf("Int64 %s overflows 64 bits", value)
+		}
+		return fmt.Errorf("Invalid Int64 %s", text)
+	}
+
+	*i = Int64(n)
+	return nil
+}
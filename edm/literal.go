@@ -0,0 +1,44 @@
+package edm
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EscapeString returns s as an OData string literal for use in a $filter
+// expression or key. Embedded quotes are doubled, O'Brien is escaped as:
+//
+//	'O''Brien'
+func EscapeString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// StringLiteral is the same as EscapeString
+func StringLiteral(s string) string {
+	return EscapeString(s)
+}
+
+// GUIDLiteral returns s in the guid'...' form used in $filter expressions
+func GUIDLiteral(s string) string {
+	if g, err := ParseGUID(s); err == nil {
+		return g.Literal()
+	}
+
+	// not a valid guid: make sure it can't break out of the literal
+	return "guid" + EscapeString(s)
+}
+
+// DateTimeLiteral returns t in the datetime'2006-01-02T15:04:05' form used in
+// $filter expressions. The literal has no timezone: t is written in its own
+// location.
+func DateTimeLiteral(t time.Time) string {
+	return "datetime'" + t.Format("2006-01-02T15:04:05.999") + "'"
+}
+
+// Int64Literal returns n with the L suffix Exact Online expects for Edm.Int64
+// values such as Timestamp: 12345L. Without the suffix the comparison is done
+// on a different type and silently returns the wrong records.
+func Int64Literal(n int64) string {
+	return strconv.FormatInt(n, 10) + "L"
+}
@@ -0,0 +1,45 @@
+package edm
+
+import (
+	"testing"
+)
+
+func TestEscapeString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Exact", "'Exact'"},
+		{"", "''"},
+		{"O'Brien", "'O''Brien'"},
+		{"D'Angelo", "'D''Angelo'"},
+		{"'quoted'", "'''quoted'''"},
+		{"It''s", "'It''''s'"},
+	}
+
+	for _, test := range tests {
+		got := EscapeString(test.in)
+		if got != test.want {
+			t.Errorf("EscapeString(%q) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
+
+func TestInt64Literal(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0L"},
+		{12345, "12345L"},
+		{-1, "-1L"},
+		{9223372036854775807, "9223372036854775807L"},
+	}
+
+	for _, test := range tests {
+		got := Int64Literal(test.in)
+		if got != test.want {
+			t.Errorf("Int64Literal(%d) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
@@ -1,8 +1,29 @@
 package edm
 
-import "github.com/tim-online/go-exactonline/utils"
+import (
+	"encoding/json"
 
+	"github.com/tim-online/go-exactonline/utils"
+)
+
+// MetaData is the __metadata block of an entity
 type MetaData struct {
-	URL  utils.URL `json:"uri"`
-	Type string    `json:"type"`
+	// Canonical uri of the entity, use it for MERGE and DELETE calls
+	URL utils.URL `json:"uri"`
+
+	// OData type, e.g. Exact.Web.Api.Models.Account
+	Type string `json:"type"`
+
+	// Only set for entities that support optimistic concurrency
+	ETag string `json:"etag,omitempty"`
+}
+
+// ParseMetaData reads the __metadata block of a raw entity
+func ParseMetaData(entity json.RawMessage) (MetaData, error) {
+	v := struct {
+		MetaData MetaData `json:"__metadata"`
+	}{}
+
+	err := json.Unmarshal(entity, &v)
+	return v.MetaData, err
 }
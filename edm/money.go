@@ -0,0 +1,78 @@
+package edm
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Money pairs an amount with its ISO 4217 currency code, e.g. AmountFC with
+// Currency of a transaction line. Arithmetic refuses to mix currencies.
+type Money struct {
+	Amount   Decimal
+	Currency string
+}
+
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount.Rat().Sign() == 0
+}
+
+// Add returns m + o. Both amounts must have the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("Can't add %s to %s", o.Currency, m.Currency)
+	}
+
+	r := new(big.Rat).Add(m.Amount.Rat(), o.Amount.Rat())
+	return Money{Amount: decimalFromRat(r), Currency: m.Currency}, nil
+}
+
+// Sub returns m - o. Both amounts must have the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("Can't subtract %s from %s", o.Currency, m.Currency)
+	}
+
+	r := new(big.Rat).Sub(m.Amount.Rat(), o.Amount.Rat())
+	return Money{Amount: decimalFromRat(r), Currency: m.Currency}, nil
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	r := new(big.Rat).Neg(m.Amount.Rat())
+	return Money{Amount: decimalFromRat(r), Currency: m.Currency}
+}
+
+// Format returns the amount rounded to places decimals followed by the
+// currency, e.g. "12.35 EUR". Halves are rounded away from zero.
+func (m Money) Format(places int) string {
+	s := m.Amount.Rat().FloatString(places)
+	if m.Currency == "" {
+		return s
+	}
+	return s + " " + m.Currency
+}
+
+// String returns the exact amount followed by the currency
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return m.Amount.String() + " " + m.Currency
+}
+
+// decimalFromRat converts the result of adding or subtracting decimals back
+// to a decimal, which always has a finite number of decimals
+func decimalFromRat(r *big.Rat) Decimal {
+	places := 0
+	scaled := new(big.Rat).Set(r)
+	ten := big.NewRat(10, 1)
+	for !scaled.IsInt() {
+		scaled.Mul(scaled, ten)
+		places++
+	}
+	return Decimal{value: r.FloatString(places)}
+}
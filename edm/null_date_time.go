@@ -0,0 +1,55 @@
+package edm
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NullDateTime is a DateTime that may be null. Valid is false when the value
+// is null or empty, so an absent date can be told apart from a real one.
+type NullDateTime struct {
+	time.Time
+	Valid bool
+}
+
+func NewNullDateTime(t time.Time) NullDateTime {
+	return NullDateTime{Time: t, Valid: true}
+}
+
+// MarshalJSON writes the time in the /Date(1488939627017)/ notation or null
+// when not valid
+func (d NullDateTime) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return json.Marshal(nil)
+	}
+
+	return json.Marshal(fmt.Sprintf("/Date(%d)/", d.Time.UnixMilli()))
+}
+
+func (d NullDateTime) IsEmpty() bool {
+	return !d.Valid
+}
+
+func (d *NullDateTime) UnmarshalJSON(text []byte) (err error) {
+	d.Time = time.Time{}
+	d.Valid = false
+
+	var value *string
+	err = json.Unmarshal(text, &value)
+	if err != nil {
+		return err
+	}
+
+	if value == nil || *value == "" {
+		return nil
+	}
+
+	d.Time, err = parseDateTime(*value)
+	if err != nil {
+		return err
+	}
+
+	d.Valid = true
+	return nil
+}
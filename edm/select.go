@@ -0,0 +1,79 @@
+package edm
+
+import (
+	"reflect"
+	"strings"
+)
+
+// navigation is implemented by the navigation property types
+type navigation interface {
+	navigation()
+}
+
+func (Deferred) navigation()    {}
+func (Expanded[T]) navigation() {}
+
+var navigationType = reflect.TypeOf((*navigation)(nil)).Elem()
+
+// SelectFields returns the json names of the fields of the struct v, a pointer
+// to it or a slice of it, for use as $select. Fields tagged with "-" and the
+// __metadata block are left out and fields of embedded structs are promoted.
+// Navigation properties (Deferred and Expanded fields) are skipped as well:
+// select their fields together with $expand, e.g. "SalesOrderLines/ID".
+func SelectFields(v interface{}) []string {
+	typ := reflect.TypeOf(v)
+	for typ != nil {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			typ = typ.Elem()
+			continue
+		}
+		break
+	}
+
+	fields := []string{}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fields
+	}
+	return selectFields(typ, fields)
+}
+
+func selectFields(typ reflect.Type, fields []string) []string {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		// fields of embedded structs are promoted
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			fields = selectFields(fieldType, fields)
+			continue
+		}
+
+		if field.PkgPath != "" || name == "__metadata" || fieldType == reflect.TypeOf(MetaData{}) {
+			continue
+		}
+
+		if fieldType.Implements(navigationType) {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
@@ -0,0 +1,101 @@
+package edm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time is a time of day or duration like PT8H30M
+type Time struct {
+	time.Duration
+}
+
+// PT8H30M15.5S, optionally with days: P1DT2H
+var timePattern = regexp.MustCompile(`^(-)?P(?:([0-9]+)D)?(?:T(?:([0-9]+)H)?(?:([0-9]+)M)?(?:([0-9]+(?:\.[0-9]+)?)S)?)?$`)
+
+// ParseTime parses an ISO 8601 duration in the PTnHnMnS notation
+func ParseTime(s string) (Time, error) {
+	m := timePattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return Time{}, fmt.Errorf("Invalid time %s", s)
+	}
+
+	d := time.Duration(0)
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+
+		n, err := strconv.ParseInt(m[i+2], 10, 64)
+		if err != nil {
+			return Time{}, err
+		}
+		d += time.Duration(n) * unit
+	}
+
+	if m[5] != "" {
+		seconds, err := strconv.ParseFloat(m[5], 64)
+		if err != nil {
+			return Time{}, err
+		}
+		d += time.Duration(seconds * float64(time.Second))
+	}
+
+	if m[1] == "-" {
+		d = -d
+	}
+
+	return Time{d}, nil
+}
+
+// String returns the time in the PTnHnMnS notation
+func (t Time) String() string {
+	d := t.Duration
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+
+	s := sign + "PT"
+	if hours > 0 {
+		s += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 {
+		s += fmt.Sprintf("%dM", minutes)
+	}
+	if d > 0 || (hours == 0 && minutes == 0) {
+		s += strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+	}
+	return s
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *Time) UnmarshalJSON(text []byte) (err error) {
+	var value string
+	err = json.Unmarshal(text, &value)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		t.Duration = 0
+		return nil
+	}
+
+	*t, err = ParseTime(value)
+	return err
+}
@@ -0,0 +1,216 @@
+// Package filter builds OData $filter expressions with correctly formatted
+// literals.
+//
+//	f := filter.And(
+//		filter.Eq("Status", 20),
+//		filter.Gt("Modified", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+//		filter.Contains("Name", "O'Brien"),
+//	)
+//	opts := &odata.QueryOptions{Filter: f.String()}
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tim-online/go-exactonline/edm"
+	"github.com/tim-online/go-exactonline/odata"
+)
+
+// Filter is an OData filter expression. The zero Filter is empty and is left
+// out of And and Or.
+type Filter struct {
+	expr string
+}
+
+// Raw returns a filter for a hand written expression
+func Raw(expr string) Filter {
+	return Filter{expr: expr}
+}
+
+func (f Filter) String() string {
+	return f.expr
+}
+
+func (f Filter) IsEmpty() bool {
+	return f.expr == ""
+}
+
+// And combines f with other filters
+func (f Filter) And(filters ...Filter) Filter {
+	return And(append([]Filter{f}, filters...)...)
+}
+
+// Or combines f with other filters
+func (f Filter) Or(filters ...Filter) Filter {
+	return Or(append([]Filter{f}, filters...)...)
+}
+
+func Eq(field string, value interface{}) Filter {
+	return compare(field, "eq", value)
+}
+
+func Ne(field string, value interface{}) Filter {
+	return compare(field, "ne", value)
+}
+
+func Gt(field string, value interface{}) Filter {
+	return compare(field, "gt", value)
+}
+
+func Ge(field string, value interface{}) Filter {
+	return compare(field, "ge", value)
+}
+
+func Lt(field string, value interface{}) Filter {
+	return compare(field, "lt", value)
+}
+
+func Le(field string, value interface{}) Filter {
+	return compare(field, "le", value)
+}
+
+// And returns a filter matching all filters
+func And(filters ...Filter) Filter {
+	return join("and", filters)
+}
+
+// Or returns a filter matching any of the filters
+func Or(filters ...Filter) Filter {
+	return join("or", filters)
+}
+
+// Not negates f
+func Not(f Filter) Filter {
+	if f.IsEmpty() {
+		return f
+	}
+	return Filter{expr: "not (" + f.expr + ")"}
+}
+
+// Contains matches fields containing substr. Note substringof takes the
+// needle first: substringof('abc', Name).
+func Contains(field, substr string) Filter {
+	return Filter{expr: fmt.Sprintf("substringof(%s, %s) eq true", edm.EscapeString(substr), field)}
+}
+
+// StartsWith matches fields starting with prefix: startswith(Name, 'abc')
+func StartsWith(field, prefix string) Filter {
+	return Filter{expr: fmt.Sprintf("startswith(%s, %s) eq true", field, edm.EscapeString(prefix))}
+}
+
+// EndsWith matches fields ending with suffix: endswith(Name, 'abc')
+func EndsWith(field, suffix string) Filter {
+	return Filter{expr: fmt.Sprintf("endswith(%s, %s) eq true", field, edm.EscapeString(suffix))}
+}
+
+// FromStruct returns a filter matching all non-empty fields of struct v with
+// eq, using the json names of the fields
+func FromStruct(v interface{}) (Filter, error) {
+	fields, err := odata.NonZeroFields(v)
+	if err != nil {
+		return Filter{}, err
+	}
+
+	values, err := odata.PartialBody(v, fields)
+	if err != nil {
+		return Filter{}, err
+	}
+
+	filters := []Filter{}
+	for _, field := range fields {
+		filters = append(filters, Eq(field, values[field]))
+	}
+	return And(filters...), nil
+}
+
+func compare(field, operator string, value interface{}) Filter {
+	return Filter{expr: field + " " + operator + " " + Literal(value)}
+}
+
+func join(operator string, filters []Filter) Filter {
+	exprs := []string{}
+	for _, f := range filters {
+		if f.IsEmpty() {
+			continue
+		}
+		exprs = append(exprs, f.expr)
+	}
+
+	switch len(exprs) {
+	case 0:
+		return Filter{}
+	case 1:
+		return Filter{expr: exprs[0]}
+	}
+	return Filter{expr: "(" + strings.Join(exprs, ") "+operator+" (") + ")"}
+}
+
+// literaler is implemented by types that know their OData literal, such as
+// custom enums built with edm.Enum
+type literaler interface {
+	Literal() string
+}
+
+// Literal formats value as an OData literal: strings are quoted, guids and
+// times get their guid'...' and datetime'...' prefix and int64 values the L
+// suffix
+func Literal(value interface{}) string {
+	// check pointers first: the methods of the value types would panic on nil
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "null"
+		}
+		return Literal(rv.Elem().Interface())
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case Filter:
+		return v.expr
+	case string:
+		return edm.EscapeString(v)
+	case edm.String:
+		return edm.EscapeString(string(v))
+	case edm.GUID:
+		return v.Literal()
+	case time.Time:
+		return edm.DateTimeLiteral(v)
+	case edm.DateTime:
+		return edm.DateTimeLiteral(v.Time)
+	case edm.Date:
+		return edm.DateTimeLiteral(v.Time)
+	case edm.Decimal:
+		return v.String()
+	case edm.Int64:
+		return edm.Int64Literal(int64(v))
+	case int64:
+		return edm.Int64Literal(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case edm.Boolean:
+		return strconv.FormatBool(bool(v))
+	case literaler:
+		return v.Literal()
+	case fmt.Stringer:
+		return edm.EscapeString(v.String())
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(value)
+	case reflect.Int64:
+		return edm.Int64Literal(rv.Int())
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	case reflect.String:
+		return edm.EscapeString(rv.String())
+	}
+	return edm.EscapeString(fmt.Sprint(value))
+}
@@ -0,0 +1,161 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tim-online/go-exactonline/edm"
+)
+
+type status string
+
+func (s status) Literal() string {
+	return edm.EscapeString(string(s))
+}
+
+func TestLiteral(t *testing.T) {
+	guid, err := edm.ParseGUID("bd1a2f33-5f57-4ed8-b8ff-3f1d09a8b6c7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount, err := edm.NewDecimal("12.50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	name := "O'Brien"
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"nil", nil, "null"},
+		{"string", "Exact", "'Exact'"},
+		{"quoted string", "O'Brien", "'O''Brien'"},
+		{"edm string", edm.String("It's"), "'It''s'"},
+		{"int", 20, "20"},
+		{"int64", int64(12345), "12345L"},
+		{"edm int64", edm.Int64(7), "7L"},
+		{"float", 1.5, "1.5"},
+		{"bool", true, "true"},
+		{"guid", guid, "guid'bd1a2f33-5f57-4ed8-b8ff-3f1d09a8b6c7'"},
+		{"time", modified, "datetime'2023-01-02T15:04:05'"},
+		{"datetime", edm.DateTime{Time: modified}, "datetime'2023-01-02T15:04:05'"},
+		{"decimal", amount, "12.50"},
+		{"literal method", status("A"), "'A'"},
+		{"filter", Raw("Name eq 'x'"), "Name eq 'x'"},
+		{"string pointer", &name, "'O''Brien'"},
+		{"guid pointer", &guid, "guid'bd1a2f33-5f57-4ed8-b8ff-3f1d09a8b6c7'"},
+		{"decimal pointer", &amount, "12.50"},
+		{"nil string pointer", (*string)(nil), "null"},
+		{"nil guid pointer", (*edm.GUID)(nil), "null"},
+		{"nil decimal pointer", (*edm.Decimal)(nil), "null"},
+		{"nil datetime pointer", (*edm.DateTime)(nil), "null"},
+	}
+
+	for _, test := range tests {
+		got := Literal(test.value)
+		if got != test.want {
+			t.Errorf("%s: Literal(%#v) = %s, want %s", test.name, test.value, got, test.want)
+		}
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		// substringof takes the needle first
+		{Contains("Name", "O'Brien"), "substringof('O''Brien', Name) eq true"},
+		{StartsWith("Name", "Ex"), "startswith(Name, 'Ex') eq true"},
+		{EndsWith("Name", "'s"), "endswith(Name, '''s') eq true"},
+		{Eq("Status", 20), "Status eq 20"},
+		{Ne("Code", "A"), "Code ne 'A'"},
+		{Gt("Timestamp", int64(10)), "Timestamp gt 10L"},
+		{Le("Amount", (*edm.Decimal)(nil)), "Amount le null"},
+	}
+
+	for _, test := range tests {
+		if got := test.filter.String(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	a := Eq("A", 1)
+	b := Eq("B", 2)
+
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{And(a, b), "(A eq 1) and (B eq 2)"},
+		{Or(a, b), "(A eq 1) or (B eq 2)"},
+		{a.And(b, Eq("C", 3)), "(A eq 1) and (B eq 2) and (C eq 3)"},
+		{And(a, Filter{}), "A eq 1"},
+		{And(), ""},
+		{Not(a), "not (A eq 1)"},
+		{Not(Filter{}), ""},
+	}
+
+	for _, test := range tests {
+		if got := test.filter.String(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestFromStruct(t *testing.T) {
+	v := struct {
+		Name     string        `json:"Name"`
+		Status   int           `json:"Status"`
+		Modified *edm.DateTime `json:"Modified"`
+		Amount   *edm.Decimal  `json:"Amount"`
+	}{Name: "Exact"}
+
+	f, err := FromStruct(&v)
+	if err != nil {
+		t.Fatalf("FromStruct returned error: %s", err)
+	}
+
+	want := "Name eq 'Exact'"
+	if got := f.String(); got != want {
+		t.Errorf("FromStruct = %s, want %s", got, want)
+	}
+}
+
+type enumStatus string
+
+var statuses = edm.NewEnum[enumStatus]("Status", "A", "P")
+
+func (s enumStatus) Literal() string {
+	return statuses.Literal(s)
+}
+
+// String returns a description, which must not end up in the filter
+func (s enumStatus) String() string {
+	return "Active"
+}
+
+func TestLiteralEnum(t *testing.T) {
+	active := enumStatus("A")
+
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{active, "'A'"},
+		{&active, "'A'"},
+		{(*enumStatus)(nil), "null"},
+		{(*status)(nil), "null"},
+	}
+
+	for _, test := range tests {
+		if got := Literal(test.value); got != test.want {
+			t.Errorf("Literal(%#v) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
@@ -18,7 +18,7 @@ const (
 func (s *Service) GLAccountsGet(requestParams *GLAccountsGetParams, ctx context.Context) (*GLAccountsGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewGLAccountsGetResponse()
-	path := s.rest.SubPath(GLAccountsEndpoint)
+	path := GLAccountsEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
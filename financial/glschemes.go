@@ -18,7 +18,7 @@ const (
 func (s *Service) GLSchemesGet(requestParams *GLSchemesGetParams, ctx context.Context) (*GLSchemesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewGLSchemesGetResponse()
-	path := s.rest.SubPath(GLSchemesEndpoint)
+	path := GLSchemesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
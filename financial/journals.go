@@ -18,7 +18,7 @@ const (
 func (s *Service) JournalsGet(requestParams *JournalsGetParams, ctx context.Context) (*JournalsGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewJournalsGetResponse()
-	path := s.rest.SubPath(JournalsEndpoint)
+	path := JournalsEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
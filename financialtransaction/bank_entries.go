@@ -18,7 +18,7 @@ const (
 func (s *Service) BankEntriesGet(requestParams *BankEntriesGetParams, ctx context.Context) (*BankEntriesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewBankEntriesGetResponse()
-	path := s.rest.SubPath(BankEntriesEndpoint)
+	path := BankEntriesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -18,7 +18,7 @@ const (
 func (s *Service) BankEntryLinesGet(requestParams *BankEntryLinesGetParams, ctx context.Context) (*BankEntryLinesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewBankEntryLinesGetResponse()
-	path := s.rest.SubPath(BankEntryLinesEndpoint)
+	path := BankEntryLinesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -18,7 +18,7 @@ const (
 func (s *Service) TransactionLinesGet(requestParams *TransactionLinesGetParams, ctx context.Context) (*TransactionLinesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewTransactionLinesGetResponse()
-	path := s.rest.SubPath(TransactionLinesEndpoint)
+	path := TransactionLinesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -18,7 +18,7 @@ const (
 func (s *Service) TransactionsGet(requestParams *TransactionsGetParams, ctx context.Context) (*TransactionsGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewTransactionsGetResponse()
-	path := s.rest.SubPath(TransactionsEndpoint)
+	path := TransactionsEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
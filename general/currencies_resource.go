@@ -28,7 +28,7 @@ type CurrenciesResource struct {
 func (r *CurrenciesResource) Get(requestParams *CurrenciesGetParams, ctx context.Context) (*CurrenciesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := r.NewGetResponse()
-	path := r.Path()
+	path := r.endpoint
 
 	// create a new HTTP request
 	httpReq, err := r.rest.NewRequest(ctx, method, path, nil)
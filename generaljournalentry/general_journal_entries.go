@@ -20,7 +20,7 @@ const (
 func (s *Service) GeneralJournalEntriesGet(requestParams *GeneralJournalEntriesGetParams, ctx context.Context) (*GeneralJournalEntriesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewGeneralJournalEntriesGetResponse()
-	path := s.rest.SubPath(GeneralJournalEntriesEndpoint)
+	path := GeneralJournalEntriesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -10,7 +10,7 @@ import (
 func (s *Service) GeneralJournalEntriesPost(body *GeneralJournalEntriesPostBody, ctx context.Context) (*GeneralJournalEntriesPostResponse, error) {
 	method := http.MethodPost
 	responseBody := s.NewGeneralJournalEntriesPostResponse()
-	path := s.rest.SubPath(GeneralJournalEntriesEndpoint)
+	path := GeneralJournalEntriesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, body)
@@ -18,7 +18,7 @@ const (
 func (s *Service) CostcentersGet(requestParams *CostcentersGetParams, ctx context.Context) (*CostcentersGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewCostcentersGetResponse()
-	path := s.rest.SubPath(CostcentersEndpoint)
+	path := CostcentersEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
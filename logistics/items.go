@@ -18,7 +18,7 @@ const (
 func (s *Service) ItemsGet(requestParams *ItemsGetParams, ctx context.Context) (*ItemsGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewItemsGetResponse()
-	path := s.rest.SubPath(ItemsEndpoint)
+	path := ItemsEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -0,0 +1,119 @@
+package odata
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// emptier is implemented by the edm types
+type emptier interface {
+	IsEmpty() bool
+}
+
+// NonZeroFields returns the json names of the fields of struct v that aren't
+// empty. Use it as $select or as the fields of PartialBody so an update only
+// sends the properties that are set.
+func NonZeroFields(v interface{}) ([]string, error) {
+	fields := []string{}
+	err := walkFields(v, func(name string, value reflect.Value) {
+		if !isEmptyValue(value) {
+			fields = append(fields, name)
+		}
+	})
+	return fields, err
+}
+
+// PartialBody returns a body with only the given fields of struct v, for
+// example the $select of the request v was retrieved with. Sending it in a
+// MERGE keeps properties that weren't selected, and decoded as zero values,
+// from being overwritten. Unknown fields return an error.
+func PartialBody(v interface{}, fields []string) (map[string]interface{}, error) {
+	values := map[string]reflect.Value{}
+	err := walkFields(v, func(name string, value reflect.Value) {
+		values[name] = value
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{}
+	for _, field := range fields {
+		value, ok := values[field]
+		if !ok {
+			return nil, errors.New("Unknown field " + field)
+		}
+		body[field] = value.Interface()
+	}
+	return body, nil
+}
+
+// walkFields calls fn for every exported field of struct v with its json name
+func walkFields(v interface{}, fn func(string, reflect.Value)) error {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return errors.New("Expected a struct, got nil")
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return errors.New("Expected a struct, got " + val.Kind().String())
+	}
+
+	walkStruct(val, fn)
+	return nil
+}
+
+func walkStruct(val reflect.Value, fn func(string, reflect.Value)) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		// fields of embedded structs are promoted
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			walkStruct(val.Field(i), fn)
+			continue
+		}
+
+		if field.PkgPath != "" || !val.Field(i).CanInterface() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fn(name, val.Field(i))
+	}
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	// a nil pointer would panic in a value receiver IsEmpty
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+	}
+
+	if e, ok := v.Interface().(emptier); ok {
+		return e.IsEmpty()
+	}
+
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
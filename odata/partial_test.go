@@ -0,0 +1,62 @@
+package odata_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tim-online/go-exactonline/edm"
+	"github.com/tim-online/go-exactonline/odata"
+)
+
+type patch struct {
+	Name     string        `json:"Name"`
+	Modified *edm.DateTime `json:"Modified"`
+	Amount   *edm.Decimal  `json:"Amount"`
+	Notes    *string       `json:"Notes"`
+	Extra    interface{}   `json:"Extra"`
+}
+
+func TestNonZeroFieldsNilPointers(t *testing.T) {
+	fields, err := odata.NonZeroFields(&patch{})
+	if err != nil {
+		t.Fatalf("NonZeroFields returned error: %s", err)
+	}
+	if len(fields) != 0 {
+		t.Errorf("NonZeroFields = %v, want no fields", fields)
+	}
+}
+
+func TestNonZeroFields(t *testing.T) {
+	notes := ""
+	v := &patch{
+		Name:     "Exact",
+		Modified: &edm.DateTime{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Notes:    &notes,
+	}
+
+	fields, err := odata.NonZeroFields(v)
+	if err != nil {
+		t.Fatalf("NonZeroFields returned error: %s", err)
+	}
+
+	// a pointer to an empty string is set: it clears the property
+	want := []string{"Name", "Modified", "Notes"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("NonZeroFields = %v, want %v", fields, want)
+	}
+}
+
+func TestPartialBodyNilPointer(t *testing.T) {
+	body, err := odata.PartialBody(&patch{Name: "Exact"}, []string{"Name", "Modified"})
+	if err != nil {
+		t.Fatalf("PartialBody returned error: %s", err)
+	}
+
+	if body["Name"] != "Exact" {
+		t.Errorf("PartialBody Name = %v, want Exact", body["Name"])
+	}
+	if m, ok := body["Modified"].(*edm.DateTime); !ok || m != nil {
+		t.Errorf("PartialBody Modified = %v, want a nil *edm.DateTime", body["Modified"])
+	}
+}
@@ -0,0 +1,108 @@
+package odata
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryOptions holds the OData system query options of a request
+type QueryOptions struct {
+	// $filter=Name eq 'Exact'
+	Filter string
+
+	// $select=ID,Name
+	Select []string
+
+	// $expand=BankAccounts
+	Expand []string
+
+	// $orderby=Name desc
+	OrderBy []string
+
+	// $top=10
+	Top int
+
+	// $skip=10
+	Skip int
+
+	// $skiptoken=guid'00000000-0000-0000-0000-000000000000'
+	SkipToken string
+
+	// $inlinecount=allpages
+	InlineCount bool
+
+	// $format=csv
+	Format string
+}
+
+// Encode returns the query options as url values. Empty options are left out.
+func (o *QueryOptions) Encode() url.Values {
+	values := url.Values{}
+	if o == nil {
+		return values
+	}
+
+	if o.Filter != "" {
+		values.Set("$filter", o.Filter)
+	}
+	if len(o.Select) > 0 {
+		values.Set("$select", strings.Join(o.Select, ","))
+	}
+	if len(o.Expand) > 0 {
+		values.Set("$expand", strings.Join(o.Expand, ","))
+	}
+	if len(o.OrderBy) > 0 {
+		values.Set("$orderby", strings.Join(o.OrderBy, ","))
+	}
+	if o.Top > 0 {
+		values.Set("$top", strconv.Itoa(o.Top))
+	}
+	if o.Skip > 0 {
+		values.Set("$skip", strconv.Itoa(o.Skip))
+	}
+	if o.SkipToken != "" {
+		values.Set("$skiptoken", o.SkipToken)
+	}
+	if o.InlineCount {
+		values.Set("$inlinecount", "allpages")
+	}
+	if o.Format != "" {
+		values.Set("$format", o.Format)
+	}
+
+	return values
+}
+
+// QueryString returns the query options encoded for use in a url
+func (o *QueryOptions) QueryString() string {
+	return EncodeQuery(o.Encode())
+}
+
+// EncodeQuery encodes url values like url.Values.Encode but in a form OData
+// understands: spaces become %20 instead of + and the $ of the system query
+// options and the commas separating fields are kept as is.
+func EncodeQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, k := range keys {
+		for _, v := range values[k] {
+			pairs = append(pairs, escape(k)+"="+escape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func escape(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.Replace(s, "+", "%20", -1)
+	s = strings.Replace(s, "%24", "$", -1)
+	s = strings.Replace(s, "%2C", ",", -1)
+	return s
+}
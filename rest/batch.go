@@ -0,0 +1,426 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+const (
+	BatchEndpoint = "/v1/{division}/$batch"
+)
+
+// NewBatchRequest returns an empty batch. Operations are sent in the order
+// they're added.
+func NewBatchRequest() *BatchRequest {
+	return &BatchRequest{}
+}
+
+// BatchRequest bundles multiple operations in a single OData $batch request
+type BatchRequest struct {
+	parts []batchPart
+}
+
+// batchPart is either a single operation or a changeset
+type batchPart struct {
+	operation *BatchOperation
+	changeset *Changeset
+}
+
+// BatchOperation is a single request inside a batch
+type BatchOperation struct {
+	Method string
+	Path   string
+	Body   interface{}
+
+	// Extra headers of the operation, e.g. If-Match
+	Header http.Header
+
+	// url the operation was sent to
+	url string
+}
+
+// Changeset groups write operations that Exact Online commits atomically:
+// either all operations succeed or none of them are applied. When one of them
+// fails, for example with 412 Precondition Failed or 409 Conflict, the error
+// is set on the result of every operation of the changeset.
+type Changeset struct {
+	operations []*BatchOperation
+}
+
+// Add adds an operation to the batch
+func (b *BatchRequest) Add(method, path string, body interface{}) *BatchOperation {
+	op := &BatchOperation{Method: method, Path: path, Body: body}
+	b.parts = append(b.parts, batchPart{operation: op})
+	return op
+}
+
+// Changeset adds a new changeset to the batch
+func (b *BatchRequest) Changeset() *Changeset {
+	cs := &Changeset{}
+	b.parts = append(b.parts, batchPart{changeset: cs})
+	return cs
+}
+
+// Add adds a write operation to the changeset
+func (cs *Changeset) Add(method, path string, body interface{}) *BatchOperation {
+	op := &BatchOperation{Method: method, Path: path, Body: body}
+	cs.operations = append(cs.operations, op)
+	return op
+}
+
+// Merge adds a MERGE of path with body to the changeset
+func (cs *Changeset) Merge(path string, body interface{}) *BatchOperation {
+	return cs.Add(MethodMerge, path, body)
+}
+
+// Delete adds a DELETE of path to the changeset. Deleting multiple entities in
+// a changeset deletes either all or none of them.
+func (cs *Changeset) Delete(path string) *BatchOperation {
+	return cs.Add(http.MethodDelete, path, nil)
+}
+
+// SetIfMatch only applies the operation when the entity still has etag. A
+// mismatch fails with an error that matches ErrPreconditionFailed.
+func (op *BatchOperation) SetIfMatch(etag string) *BatchOperation {
+	if op.Header == nil {
+		op.Header = http.Header{}
+	}
+	op.Header.Set("If-Match", etag)
+	return op
+}
+
+// Operations returns all operations of the batch in order
+func (b *BatchRequest) Operations() []*BatchOperation {
+	ops := []*BatchOperation{}
+	for _, part := range b.parts {
+		if part.changeset != nil {
+			ops = append(ops, part.changeset.operations...)
+			continue
+		}
+		ops = append(ops, part.operation)
+	}
+	return ops
+}
+
+// BatchResult is the response to a single operation of a batch
+type BatchResult struct {
+	Operation  *BatchOperation
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Err is set when the operation failed
+	Err error
+}
+
+// Decode decodes the {"d": ...} envelope of the operation response in v
+func (r *BatchResult) Decode(v interface{}) error {
+	if r.Err != nil {
+		return r.Err
+	}
+
+	if r.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(r.Body)) == 0 {
+		return ErrEmptyBody
+	}
+
+	_, err := decodeBody(bytes.NewReader(r.Body), v)
+	if err != nil {
+		body := r.Body
+		if len(body) > decodeErrorSnippetSize {
+			body = body[:decodeErrorSnippetSize]
+		}
+
+		return &DecodeError{
+			Method: r.Operation.Method,
+			URL:    r.Operation.url,
+			Body:   body,
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// DoBatch sends the operations of batch in a single request. The results are
+// returned in the same order as batch.Operations(). When a changeset fails
+// Exact Online returns one error for the whole changeset: that error is set
+// on the result of every operation in the changeset.
+func (c *Client) DoBatch(ctx context.Context, batch *BatchRequest) ([]*BatchResult, *http.Response, error) {
+	body, contentType, err := c.encodeBatch(batch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.NewRequest(ctx, http.MethodPost, BatchEndpoint, body,
+		WithContentType(contentType), WithHeader("Accept", "multipart/mixed"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, done := c.startMetrics(req)
+	results, httpResp, err := c.doBatch(req, batch)
+	done(httpResp, err)
+	return results, httpResp, err
+}
+
+// doBatch sends the batch request and decodes the response of DoBatch
+func (c *Client) doBatch(req *http.Request, batch *BatchRequest) ([]*BatchResult, *http.Response, error) {
+	req, cancel := c.withTimeout(req)
+	defer cancel()
+
+	httpResp, err := c.send(req)
+	if httpResp == nil {
+		return nil, nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if err != nil {
+		return nil, httpResp, err
+	}
+
+	results, err := c.decodeBatch(httpResp, batch)
+	return results, httpResp, err
+}
+
+// encodeBatch writes the multipart/mixed body of the batch
+func (c *Client) encodeBatch(batch *BatchRequest) (*bytes.Buffer, string, error) {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+
+	for _, part := range batch.parts {
+		if part.operation != nil {
+			err := c.writeBatchOperation(w, part.operation)
+			if err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		// a changeset is a nested multipart/mixed part
+		csBuf := new(bytes.Buffer)
+		csw := multipart.NewWriter(csBuf)
+		for _, op := range part.changeset.operations {
+			err := c.writeBatchOperation(csw, op)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		err := csw.Close()
+		if err != nil {
+			return nil, "", err
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "multipart/mixed; boundary="+csw.Boundary())
+		pw, err := w.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		_, err = io.Copy(pw, csBuf)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	err := w.Close()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return buf, "multipart/mixed; boundary=" + w.Boundary(), nil
+}
+
+// writeBatchOperation writes an operation as an application/http part
+func (c *Client) writeBatchOperation(w *multipart.Writer, op *BatchOperation) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "application/http")
+	header.Set("Content-Transfer-Encoding", "binary")
+	err := c.checkDivision(op.Path, c.Division())
+	if err != nil {
+		return err
+	}
+
+	pw, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	op.url = c.GetEndpoint(c.SubPath(op.Path)).String()
+	fmt.Fprintf(pw, "%s %s HTTP/1.1\r\n", op.Method, op.url)
+	fmt.Fprintf(pw, "Accept: %s\r\n", c.acceptHeader())
+	// sorted, so the same batch always has the same body
+	keys := make([]string, 0, len(op.Header))
+	for key := range op.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range op.Header[key] {
+			fmt.Fprintf(pw, "%s: %s\r\n", key, value)
+		}
+	}
+
+	if op.Body == nil {
+		fmt.Fprint(pw, "\r\n")
+		return nil
+	}
+
+	b, err := json.Marshal(op.Body)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(pw, "Content-Type: %s\r\n", c.contentType())
+	fmt.Fprintf(pw, "Content-Length: %d\r\n\r\n", len(b))
+	_, err = pw.Write(b)
+	return err
+}
+
+// decodeBatch reads the multipart/mixed response and maps the responses back
+// to the operations of the batch
+func (c *Client) decodeBatch(httpResp *http.Response, batch *BatchRequest) ([]*BatchResult, error) {
+	reader, err := multipartReader(httpResp.Header.Get("Content-Type"), httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*BatchResult{}
+	for _, part := range batch.parts {
+		p, err := reader.NextPart()
+		if err != nil {
+			return results, fmt.Errorf("Batch response is missing parts: %s", err)
+		}
+
+		if part.operation != nil {
+			result, err := readBatchResult(p, part.operation)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+			continue
+		}
+
+		csResults, err := readChangesetResults(p, part.changeset)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, csResults...)
+	}
+
+	return results, nil
+}
+
+// readChangesetResults reads the responses of a changeset. A failed changeset
+// is answered with a single application/http part instead of a multipart.
+func readChangesetResults(p *multipart.Part, cs *Changeset) ([]*BatchResult, error) {
+	results := []*BatchResult{}
+
+	contentType := p.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "multipart/mixed") {
+		result, err := readBatchResult(p, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range cs.operations {
+			r := *result
+			r.Operation = op
+			r.Err = batchError(&r)
+			results = append(results, &r)
+		}
+		return results, nil
+	}
+
+	reader, err := multipartReader(contentType, p)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range cs.operations {
+		csp, err := reader.NextPart()
+		if err != nil {
+			return results, fmt.Errorf("Changeset response is missing parts: %s", err)
+		}
+
+		result, err := readBatchResult(csp, op)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// readBatchResult parses the application/http response of an operation
+func readBatchResult(p *multipart.Part, op *BatchOperation) (*BatchResult, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(p), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BatchResult{
+		Operation:  op,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+	if op != nil {
+		result.Err = batchError(result)
+	}
+	return result, nil
+}
+
+// batchError converts a failed operation response in an ErrorResponse
+func batchError(result *BatchResult) error {
+	if c := result.StatusCode; c >= 200 && c <= 299 {
+		return nil
+	}
+
+	req, err := http.NewRequest(result.Operation.Method, result.Operation.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp := &http.Response{
+		StatusCode: result.StatusCode,
+		Header:     result.Header,
+		Body:       ioutil.NopCloser(bytes.NewReader(result.Body)),
+		Request:    req,
+	}
+	return CheckResponse(resp)
+}
+
+func multipartReader(contentType string, body io.Reader) (*multipart.Reader, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, errors.New("Expected a multipart batch response, got " + contentType)
+	}
+
+	return multipart.NewReader(body, params["boundary"]), nil
+}
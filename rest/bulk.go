@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxPageSize is the number of records regular endpoints return per page
+	MaxPageSize = 60
+	// MaxBulkPageSize is the number of records /bulk/ and /sync/ endpoints
+	// return per page
+	MaxBulkPageSize = 1000
+)
+
+// bulkEndpoints maps regular resources to their bulk equivalent
+var bulkEndpoints = map[string]string{
+	"cashflow/payments":                     "bulk/Cashflow/Payments",
+	"cashflow/receivables":                  "bulk/Cashflow/Receivables",
+	"crm/accounts":                          "bulk/CRM/Accounts",
+	"crm/addresses":                         "bulk/CRM/Addresses",
+	"crm/contacts":                          "bulk/CRM/Contacts",
+	"crm/quotationlines":                    "bulk/CRM/QuotationLines",
+	"crm/quotations":                        "bulk/CRM/Quotations",
+	"documents/documentattachments":         "bulk/Documents/DocumentAttachments",
+	"documents/documents":                   "bulk/Documents/Documents",
+	"financial/glaccounts":                  "bulk/Financial/GLAccounts",
+	"financialtransaction/transactionlines": "bulk/Financial/TransactionLines",
+	"logistics/items":                       "bulk/Logistics/Items",
+	"logistics/salesitemprices":             "bulk/Logistics/SalesItemPrices",
+	"salesinvoice/salesinvoicelines":        "bulk/SalesInvoice/SalesInvoiceLines",
+	"salesinvoice/salesinvoices":            "bulk/SalesInvoice/SalesInvoices",
+	"salesorder/goodsdeliveries":            "bulk/SalesOrder/GoodsDeliveries",
+	"salesorder/goodsdeliverylines":         "bulk/SalesOrder/GoodsDeliveryLines",
+	"salesorder/salesorderlines":            "bulk/SalesOrder/SalesOrderLines",
+	"salesorder/salesorders":                "bulk/SalesOrder/SalesOrders",
+}
+
+// matches the /api/v1/{division}/ part of a path
+var divisionPrefixPattern = regexp.MustCompile(`^(/api)?/v1/(\{division\}|[0-9]+)/`)
+
+// IsBulkPath reports whether path points to a /bulk/ endpoint
+func IsBulkPath(path string) bool {
+	return strings.Contains(strings.ToLower(path), "/bulk/")
+}
+
+// PageSize returns the maximum number of records per page for path
+func PageSize(path string) int {
+	lower := strings.ToLower(path)
+	if strings.Contains(lower, "/bulk/") || strings.Contains(lower, "/sync/") {
+		return MaxBulkPageSize
+	}
+	return MaxPageSize
+}
+
+// BulkPath rewrites a regular path like /v1/{division}/crm/Accounts to its
+// bulk equivalent /v1/{division}/bulk/CRM/Accounts. Bulk endpoints return up
+// to 1000 records per page but require $select and don't support requesting
+// a single entity by key. The second return value is false when there's no
+// bulk endpoint for path.
+func BulkPath(path string) (string, bool) {
+	if IsBulkPath(path) {
+		return path, true
+	}
+
+	prefix := ""
+	resource := strings.TrimPrefix(path, "/")
+	if loc := divisionPrefixPattern.FindStringIndex(path); loc != nil {
+		prefix = path[:loc[1]-1]
+		resource = path[loc[1]:]
+	}
+	resource = strings.TrimSuffix(resource, "{id}")
+
+	bulk, ok := bulkEndpoints[strings.ToLower(resource)]
+	if !ok {
+		return path, false
+	}
+
+	return prefix + "/" + bulk, true
+}
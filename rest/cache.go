@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Cache stores the ETag and body of GET responses so unchanged entities can be
+// revalidated with If-None-Match. Get returns nil without an error when the
+// key isn't cached.
+type Cache interface {
+	Get(key string) (*CacheEntry, error)
+	Set(key string, entry *CacheEntry) error
+}
+
+// CacheEntry is a cached response body with its ETag
+type CacheEntry struct {
+	ETag string
+	Body []byte
+}
+
+// NewMemoryCache returns a Cache that keeps the entries in memory
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]*CacheEntry{}}
+}
+
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+}
+
+func (c *MemoryCache) Get(key string) (*CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries[key], nil
+}
+
+func (c *MemoryCache) Set(key string, entry *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+	return nil
+}
+
+// SetCache enables conditional GET requests: a 304 Not Modified response is
+// decoded from the cached body. Use nil to disable caching.
+func (c *Client) SetCache(cache Cache) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.cache = cache
+}
+
+// cacheKey returns the cache and the key of req in it, an empty key when the
+// request isn't cacheable
+func (c *Client) cacheKey(req *http.Request) (Cache, string) {
+	c.configMu.RLock()
+	cache := c.cache
+	c.configMu.RUnlock()
+
+	if cache == nil || req.Method != http.MethodGet {
+		return nil, ""
+	}
+	return cache, req.URL.String()
+}
+
+// cachedEntry looks up the cached response of req and adds the If-None-Match
+// header
+func (c *Client) cachedEntry(req *http.Request) (*CacheEntry, error) {
+	cache, key := c.cacheKey(req)
+	if key == "" {
+		return nil, nil
+	}
+
+	entry, err := cache.Get(key)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	if req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	return entry, nil
+}
+
+// cacheResponse stores the body of a response with an ETag and replaces the
+// response body so it can still be decoded
+func (c *Client) cacheResponse(req *http.Request, httpResp *http.Response) error {
+	cache, key := c.cacheKey(req)
+	etag := httpResp.Header.Get("ETag")
+	if key == "" || etag == "" || httpResp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	httpResp.Body.Close()
+	httpResp.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return cache.Set(key, &CacheEntry{ETag: etag, Body: b})
+}
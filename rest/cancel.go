@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"context"
+	"io"
+)
+
+// cancelBody is a response body that stops reading as soon as the request
+// context is done, so decoding a slow body returns the context error
+type cancelBody struct {
+	ctx  context.Context
+	body io.ReadCloser
+}
+
+func (b *cancelBody) Read(p []byte) (int, error) {
+	if err := b.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := b.body.Read(p)
+	if err != nil && b.ctx.Err() != nil {
+		return n, b.ctx.Err()
+	}
+	return n, err
+}
+
+func (b *cancelBody) Close() error {
+	return b.body.Close()
+}
+
+// contextError returns the error of the request context when it's done, so a
+// cancelled request isn't reported as a decode error
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -6,119 +6,445 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
-	"net/http/httputil"
+	"net/http/httptrace"
 	"net/url"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/tim-online/go-exactonline/utils"
+	"github.com/tim-online/go-exactonline/odata"
 )
 
 const (
 	mediaType                 = "application/json"
 	charset                   = "utf-8"
 	customDescriptionLanguage = "EN-US"
+
+	libraryVersion   = "0.0.1"
+	defaultUserAgent = "go-exactonline/" + libraryVersion
 )
 
 // RequestCompletionCallback defines the type of the request callback function
 type RequestCompletionCallback func(*http.Request, *http.Response)
 
-func New(http *http.Client) *Client {
-	return &Client{
-		http: http,
+// BeforeRequestFunc is called with every request right before it's sent. A
+// non-nil error aborts the request.
+type BeforeRequestFunc func(*http.Request) error
+
+// New returns a client that sends its requests with http, configured with
+// options:
+//
+//	client := rest.New(httpClient, rest.WithBaseURL(baseURL), rest.WithDivision(division))
+//
+// New panics when an option is invalid, like a base url that isn't absolute.
+// Use NewWithOptions to handle the error instead.
+func New(http *http.Client, options ...ClientOption) *Client {
+	c, err := NewWithOptions(http, options...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewWithOptions works like New but returns the error of an invalid option
+func NewWithOptions(http *http.Client, options ...ClientOption) (*Client, error) {
+	c := &Client{
+		http:                      http,
 		customDescriptionLanguage: customDescriptionLanguage,
+		userAgent:                 defaultUserAgent,
+		mediaType:                 mediaType,
+		charset:                   charset,
+	}
+
+	err := c.applyOptions(options)
+	if err != nil {
+		return nil, err
 	}
+	return c, nil
 }
 
 type Client struct {
 	// HTTP client used to communicate with the API.
 	http *http.Client
 
+	// Guards the configuration below so setters can be called while requests
+	// are running
+	configMu sync.RWMutex
+
 	// Url pointing to base Exact Online API
 	baseURL *url.URL
 
 	divisionID int
 
+	// Division returned by current/Me when caching is enabled
+	divisionMu           sync.Mutex
+	cacheCurrentDivision bool
+	currentDivision      int
+
 	// Debugging flag
 	debug bool
 
+	// Logger for debug output, the standard logger when nil
+	logger *log.Logger
+
+	// Don't scrub credentials from the debug output
+	debugNoRedact bool
+
+	// Request gzip compressed responses
+	compression bool
+
 	// Retrieve language sensitive properties such as descriptions in a specific language
 	customDescriptionLanguage string
 
 	// User agent for client
 	userAgent string
 
+	// Content-Type of request bodies
+	mediaType string
+	charset   string
+
+	// odata metadata level set in the Accept header
+	metadataLevel MetadataLevel
+
+	// Only accept responses with a {"d": ...} envelope
+	strictEnvelope bool
+
+	// Log writes instead of sending them
+	dryRun bool
+
 	// Optional function called after every successful request made to the DO APIs
 	onRequestCompleted RequestCompletionCallback
+
+	// Optional function called before every request, retries included
+	beforeRequest BeforeRequestFunc
+
+	// Optional function called with the metrics of every request
+	metricsHook MetricsHook
+
+	// Default request timeout when the context has no deadline
+	timeout time.Duration
+
+	// Number of retries after a 429 Too Many Requests response
+	maxRetries int
+
+	// Retries of transient failures
+	retryPolicy RetryPolicy
+
+	// Caps the retries of all requests
+	retryBudget *RetryBudget
+
+	// Bytes of a streamed body that are buffered for retries
+	retryBufferSize int64
+
+	// Cache for conditional GET requests
+	cache Cache
+
+	// Paces requests before they're sent
+	limiter Limiter
+
+	// Rate limits reported by the last response
+	rateLimitMu sync.Mutex
+	rateLimit   RateLimit
+
+	// Status and headers of the last response
+	lastResponseMu sync.Mutex
+	lastResponse   *http.Response
+
+	// Share a single round trip between concurrent identical GET requests
+	dedupe     bool
+	inflightMu sync.Mutex
+	inflight   map[string]*inflightCall
 }
 
-func (c *Client) SetBaseURL(baseURL *url.URL) {
+// SetBaseURL sets the url of the Exact Online API, e.g.
+// https://start.exactonline.nl/api. The url must be absolute and use https;
+// plain http is only accepted for local test servers.
+func (c *Client) SetBaseURL(baseURL *url.URL) error {
+	if baseURL == nil || !baseURL.IsAbs() || baseURL.Host == "" {
+		return fmt.Errorf("Base url %s should be an absolute url", baseURL)
+	}
+
+	if baseURL.Scheme != "https" && !(baseURL.Scheme == "http" && isLoopback(baseURL.Hostname())) {
+		return fmt.Errorf("Base url %s should use https", baseURL)
+	}
+
 	// set base url for use in http client
-	c.baseURL = baseURL
+	u := *baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	c.configMu.Lock()
+	c.baseURL = &u
+	c.configMu.Unlock()
+	return nil
 }
 
-func (c *Client) SetDivisionID(divisionID int) {
-	c.divisionID = divisionID
+func isAbsoluteURL(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://")
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// SetDivision sets the division that replaces {division} in request paths.
+// Divisions are positive integers, other values return an error.
+func (c *Client) SetDivision(division int) error {
+	err := validateDivision(division)
+	if err != nil {
+		return err
+	}
+
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.divisionID = division
+	return nil
+}
+
+// Division returns the division set with SetDivision
+func (c *Client) Division() int {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+
+	return c.divisionID
+}
+
+// BaseURL returns the url of the Exact Online API set with SetBaseURL
+func (c *Client) BaseURL() *url.URL {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+
+	return c.baseURL
+}
+
+func (c *Client) SetDivisionID(divisionID int) error {
+	return c.SetDivision(divisionID)
 }
 
 func (c *Client) SetDebug(debug bool) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
 	c.debug = debug
 }
 
+// SetOnRequestCompleted sets a function that's called with every response
+// received, retries included. Use nil to remove it.
+func (c *Client) SetOnRequestCompleted(fn RequestCompletionCallback) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.onRequestCompleted = fn
+}
+
+// SetBeforeRequest sets a function that can modify every request just before
+// it's sent, e.g. to add tracing headers. Use nil to remove it.
+func (c *Client) SetBeforeRequest(fn BeforeRequestFunc) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.beforeRequest = fn
+}
+
+// SetUserAgent identifies the application in the User-Agent header. The
+// library version is appended, e.g. "myapp/1.0 go-exactonline/0.0.1".
 func (c *Client) SetUserAgent(userAgent string) {
-	c.userAgent = userAgent
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	userAgent = strings.TrimSpace(userAgent)
+	if userAgent == "" {
+		c.userAgent = defaultUserAgent
+		return
+	}
+	c.userAgent = userAgent + " " + defaultUserAgent
+}
+
+// SetMediaType sets the Content-Type of request bodies, "application/json"
+// with charset "utf-8" by default. An empty charset leaves out the charset
+// parameter. Use WithContentType to change it for a single request.
+func (c *Client) SetMediaType(mediaType, charset string) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.mediaType = mediaType
+	c.charset = charset
 }
 
-func (c *Client) NewRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
-	path = c.SubPath(path)
+// contentType returns the Content-Type header for request bodies
+func (c *Client) contentType() string {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+
+	if c.charset == "" {
+		return c.mediaType
+	}
+	return fmt.Sprintf("%s; charset=%s", c.mediaType, c.charset)
+}
+
+// NewRequest creates an API request for path. A body is encoded as json,
+// except for an io.Reader which is sent as is: set its Content-Type with
+// WithContentType.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body interface{}, options ...RequestOption) (*http.Request, error) {
+	opts := newRequestOptions(options)
+
+	division := opts.division
+	if division == 0 {
+		division = c.Division()
+	}
+
+	err := c.checkDivision(path, division)
+	if err != nil {
+		return nil, err
+	}
+
+	path = c.subPath(path, division)
 	u := c.GetEndpoint(path)
 
 	var b io.Reader
+	var encoded []byte
+	isReader := false
 	if body != nil {
 		// determine if body is an io.Reader or should be serialized
 		if r, ok := body.(io.Reader); ok {
 			b = r
+			isReader = true
 		} else {
 			buf := new(bytes.Buffer)
-			if body != nil {
-				err := json.NewEncoder(buf).Encode(body)
-				if err != nil {
-					return nil, err
-				}
+			err := json.NewEncoder(buf).Encode(body)
+			if err != nil {
+				return nil, err
 			}
-			b = buf
+			encoded = buf.Bytes()
+			b = bytes.NewReader(encoded)
 		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), b)
+	// optionally pass along context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), b)
 	if err != nil {
 		return nil, err
 	}
 
-	// optionally pass along context
-	if ctx != nil {
-		req = req.WithContext(ctx)
+	// make the body replayable for retries and redirects
+	if encoded != nil {
+		req.ContentLength = int64(len(encoded))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(encoded)), nil
+		}
 	}
 
-	req.Header.Add("Content-Type", fmt.Sprintf("%s; charset=%s", mediaType, charset))
-	req.Header.Add("Accept", mediaType)
+	// stream files instead of using chunked encoding
+	if isReader && req.ContentLength == 0 {
+		req.ContentLength = readerSize(b)
+	}
+
+	c.addHeaders(req)
+
+	// the caller is responsible for the content type of a reader
+	if isReader {
+		req.Header.Del("Content-Type")
+	}
+
+	opts.apply(req)
+	return req, nil
+}
+
+// readerSize returns the remaining size of files and other seekable readers,
+// -1 when it's unknown
+func readerSize(r io.Reader) int64 {
+	f, ok := r.(interface {
+		io.Seeker
+		Stat() (os.FileInfo, error)
+	})
+	if !ok {
+		return -1
+	}
+
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return -1
+	}
+
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	return info.Size() - offset
+}
+
+// addHeaders adds the default headers to a request
+func (c *Client) addHeaders(req *http.Request) {
+	req.Header.Add("Content-Type", c.contentType())
+	req.Header.Add("Accept", c.acceptHeader())
+
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+
 	req.Header.Add("User-Agent", c.userAgent)
 	req.Header.Add("CustomDescriptionLanguage", c.customDescriptionLanguage)
+
+	if c.compression {
+		req.Header.Add("Accept-Encoding", "gzip")
+	}
+}
+
+// NewRequestWithOptions works like NewRequest and adds the OData query options
+// to the url of the request.
+func (c *Client) NewRequestWithOptions(ctx context.Context, method, path string, opts *odata.QueryOptions, body interface{}, options ...RequestOption) (*http.Request, error) {
+	req, err := c.NewRequest(ctx, method, path, body, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	query := opts.QueryString()
+	if query == "" {
+		return req, nil
+	}
+
+	if req.URL.RawQuery == "" {
+		req.URL.RawQuery = query
+	} else {
+		req.URL.RawQuery = req.URL.RawQuery + "&" + query
+	}
 	return req, nil
 }
 
+// SubPath replaces {division} in path with the division of the client.
+// NewRequest does the same, so prefer passing it the raw path: a path built
+// without a division set is rejected by NewRequest with ErrNoDivision.
 func (c *Client) SubPath(path string) string {
-	divisionID := strconv.Itoa(c.divisionID)
+	return c.subPath(path, c.Division())
+}
+
+// subPath works like SubPath with division instead of the client division
+func (c *Client) subPath(path string, division int) string {
+	path = c.divisionPath(path)
+	divisionID := strconv.Itoa(division)
 	path = strings.Replace(path, "{division}", divisionID, 1)
 	path = strings.Replace(path, "{id}", "", 1)
 	return path
 }
 
 func (c *Client) SubPathWithID(path string, id string) string {
-	divisionID := strconv.Itoa(c.divisionID)
+	path = c.divisionPath(path)
+	divisionID := strconv.Itoa(c.Division())
 	path = strings.Replace(path, "{division}", divisionID, 1)
 
 	if id == "" {
@@ -130,33 +456,135 @@ func (c *Client) SubPathWithID(path string, id string) string {
 	return path
 }
 
+// divisionPath prefixes paths like /crm/Accounts with /v1/{division}. Paths
+// starting with /v1/ or /api/ are kept as is, so /api/v1/current/Me still
+// works.
+func (c *Client) divisionPath(path string) string {
+	if isAbsoluteURL(path) {
+		return path
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	if strings.HasPrefix(path, "/v1/") {
+		return path
+	}
+
+	if strings.HasPrefix(path, "/api/") {
+		// the base url already points to /api
+		if base := c.BaseURL(); base != nil && strings.HasSuffix(base.Path, "/api") {
+			return strings.TrimPrefix(path, "/api")
+		}
+		return path
+	}
+
+	return "/v1/{division}" + path
+}
+
+// GetEndpoint returns the url of path relative to the base url. Absolute urls,
+// like deferred uris and __next links, are returned as is.
 func (c *Client) GetEndpoint(path string) *url.URL {
-	basePath := strings.TrimSuffix(c.baseURL.Path, "/")
+	if isAbsoluteURL(path) {
+		if u, err := url.Parse(path); err == nil {
+			return u
+		}
+	}
+
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
 
-	u := *c.baseURL
-	u.Path = basePath + path
+	u := *c.BaseURL()
+	u.Path = u.Path + path
+	u.RawPath = keyPathReplacer.Replace(u.EscapedPath())
 	return &u
 }
 
+// keep entity keys like Accounts(guid'...') readable instead of escaping the
+// parentheses and quotes
+var keyPathReplacer = strings.NewReplacer("%28", "(", "%29", ")", "%27", "'")
+
 // Do sends an API request and returns the API response. The API response is XML decoded and stored in the value
 // pointed to by v, or returned as an error if an API error has occurred. If v implements the io.Writer interface,
 // the raw response will be written to v, without attempting to decode it.
 func (c *Client) Do(req *http.Request, responseBody interface{}) (*http.Response, error) {
-	if c.debug == true {
-		dump, _ := httputil.DumpRequestOut(req, true)
-		log.Println(string(dump))
+	_, httpResp, err := c.DoPage(req, responseBody)
+	return httpResp, err
+}
+
+// RawDo sends an API request and returns the response body without decoding
+// it. Errors are checked the same way as in Do.
+func (c *Client) RawDo(req *http.Request) ([]byte, *http.Response, error) {
+	buf := new(bytes.Buffer)
+	_, httpResp, err := c.DoPage(req, buf)
+	if err != nil {
+		return nil, httpResp, err
 	}
+	return buf.Bytes(), httpResp, nil
+}
+
+// DoRaw works like Do but decodes the d field of the envelope straight into
+// v, whether it's an array or an object. Use it for endpoints that don't
+// follow the results convention.
+func (c *Client) DoRaw(req *http.Request, v interface{}) (*http.Response, error) {
+	_, httpResp, err := c.DoPage(req, &rawD{v: v})
+	return httpResp, err
+}
+
+// rawD decodes d without the results handling of decodeBody
+type rawD struct {
+	v interface{}
+}
+
+func (d *rawD) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, d.v)
+}
 
-	httpResp, err := c.http.Do(req)
+// Page holds the pagination details of a response
+type Page struct {
+	// Url of the next page, empty when this is the last page
+	Next string
+
+	// Total number of records when requested with $inlinecount=allpages,
+	// -1 otherwise
+	Count int
+}
+
+// DoPage works like Do but also returns the pagination details of the
+// response. The __next url isn't followed so the caller can page manually,
+// for example by persisting the cursor and resuming later on.
+func (c *Client) DoPage(req *http.Request, responseBody interface{}) (*Page, *http.Response, error) {
+	req, done := c.startMetrics(req)
+	page, httpResp, err := c.doPage(req, responseBody)
+	done(httpResp, err)
+	return page, httpResp, err
+}
+
+// doPage sends the request and decodes the response of DoPage
+func (c *Client) doPage(req *http.Request, responseBody interface{}) (*Page, *http.Response, error) {
+	page := &Page{Count: -1}
+
+	req, cancel := c.withTimeout(req)
+	defer cancel()
+
+	entry, err := c.cachedEntry(req)
 	if err != nil {
-		return nil, err
+		return page, nil, err
 	}
 
-	if c.onRequestCompleted != nil {
-		c.onRequestCompleted(req, httpResp)
+	httpResp, err := c.sendShared(req)
+	if httpResp == nil {
+		return page, nil, err
+	}
+
+	// not modified: use the cached body
+	if entry != nil && httpResp.StatusCode == http.StatusNotModified {
+		httpResp.Body.Close()
+		httpResp.Body = ioutil.NopCloser(bytes.NewReader(entry.Body))
+		httpResp.ContentLength = int64(len(entry.Body))
+		err = nil
 	}
 
 	// close body io.Reader
@@ -166,38 +594,81 @@ func (c *Client) Do(req *http.Request, responseBody interface{}) (*http.Response
 		}
 	}()
 
-	if c.debug == true {
-		dump, _ := httputil.DumpResponse(httpResp, true)
-		log.Println(string(dump))
+	if err != nil {
+		return page, httpResp, err
 	}
 
-	// check if the response isn't an error
-	err = CheckResponse(httpResp)
+	err = c.cacheResponse(req, httpResp)
 	if err != nil {
-		return httpResp, err
+		return page, httpResp, err
 	}
 
-	// check the provided interface parameter
-	if httpResp == nil {
-		return httpResp, err
+	if responseBody == nil {
+		return page, httpResp, err
 	}
 
-	if responseBody == nil {
-		return httpResp, err
+	// nothing to decode
+	if httpResp.StatusCode == http.StatusNoContent {
+		return page, httpResp, nil
 	}
 
 	// interface implements io.Writer: write Body to it
-	// if w, ok := response.Envelope.(io.Writer); ok {
-	// 	_, err := io.Copy(w, httpResp.Body)
-	// 	return httpResp, err
-	// }
+	if w, ok := responseBody.(io.Writer); ok {
+		_, err = io.Copy(w, httpResp.Body)
+		return page, httpResp, contextError(req.Context(), err)
+	}
+
+	if httpResp.ContentLength == 0 {
+		return page, httpResp, ErrEmptyBody
+	}
+
+	// don't try to decode maintenance pages
+	err = nonJSONResponse(httpResp, httpResp.Body)
+	if err != nil {
+		return page, httpResp, err
+	}
+
+	c.configMu.RLock()
+	strict := c.strictEnvelope
+	c.configMu.RUnlock()
+
+	body := newSnippetReader(httpResp.Body)
+	page, err = decodeEnvelope(body, responseBody, strict)
+	if isEmptyBody(err, body.snippet) {
+		return page, httpResp, ErrEmptyBody
+	}
+	if err != nil && req.Context().Err() != nil {
+		return page, httpResp, req.Context().Err()
+	}
+	if err != nil {
+		err = &DecodeError{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Body:   body.snippet,
+			Err:    err,
+		}
+	}
+	return page, httpResp, err
+}
+
+// decodeBody decodes the {"d": ...} envelope of a response body in
+// responseBody and returns the pagination details.
+func decodeBody(body io.Reader, responseBody interface{}) (*Page, error) {
+	return decodeEnvelope(body, responseBody, false)
+}
+
+// decodeEnvelope works like decodeBody. In strict mode the envelope may only
+// contain d.
+func decodeEnvelope(body io.Reader, responseBody interface{}, strict bool) (*Page, error) {
+	page := &Page{Count: -1}
 
 	// $top=1
 	// {
 	// 	"d" : {
 	// 		"results" : [
 	// 			{}
-	// 		]
+	// 		],
+	// 		"__next": ""
 	// 	}
 	// }
 
@@ -213,23 +684,50 @@ func (c *Client) Do(req *http.Request, responseBody interface{}) (*http.Response
 	// 	"d" : {
 	// 		"results": [
 	// 			{}
-	// 		]
+	// 		],
+	// 		"__next": ""
 	// 	}
 	// }
 
-	type Envelope struct {
-		D utils.JsonTester `json:"d"`
-	}
-
 	envelope := &Envelope{}
-	err = json.NewDecoder(httpResp.Body).Decode(envelope)
+	decoder := json.NewDecoder(body)
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	err := decoder.Decode(envelope)
 	if err != nil {
-		return httpResp, err
+		return page, err
+	}
+
+	if strict && len(envelope.D.RawMessage) == 0 {
+		return page, ErrNoEnvelope
 	}
 
 	// get bytes
 	b := []byte(envelope.D.RawMessage)
 
+	// check if json is an object
+	isArray := envelope.D.IsArray()
+
+	d := &D{}
+	if envelope.D.IsObject() {
+		err = json.Unmarshal(b, d)
+		if err != nil {
+			return page, err
+		}
+
+		page, err = d.Page()
+		if err != nil {
+			return page, err
+		}
+	}
+
+	// single entity requested by key: d is the entity itself
+	// {
+	// 	"d" : {}
+	// }
+	isEntity := envelope.D.IsObject() && d.Results == nil
+
 	// check if interface has ".Results" field
 	r := reflect.ValueOf(responseBody)
 	val := reflect.Indirect(r)
@@ -239,18 +737,196 @@ func (c *Client) Do(req *http.Request, responseBody interface{}) (*http.Response
 		hasResults = field.IsValid()
 	}
 
-	// check if json is an object
-	isArray := envelope.D.IsArray()
-
 	// conversie doen
 	if hasResults && isArray {
 		b = append([]byte(`{"results":`), b...)
 		b = append(b, []byte("}")...)
 
 		err = json.Unmarshal(b, responseBody)
-		return httpResp, err
+		return page, err
+	}
+
+	if hasResults && isEntity {
+		b = append([]byte(`{"results":[`), b...)
+		b = append(b, []byte("]}")...)
+
+		err = json.Unmarshal(b, responseBody)
+		return page, err
+	}
+
+	// slices receive the results directly
+	if val.Kind() == reflect.Slice && d.Results != nil {
+		err = json.Unmarshal(d.Results, responseBody)
+		return page, err
+	}
+
+	// slices receive a single entity as the only element
+	if val.Kind() == reflect.Slice && isEntity {
+		b = append([]byte("["), b...)
+		b = append(b, []byte("]")...)
+
+		err = json.Unmarshal(b, responseBody)
+		return page, err
+	}
+
+	// a struct receives the only record of an array or results, unless it
+	// decodes itself
+	_, isUnmarshaler := responseBody.(json.Unmarshaler)
+	if val.Kind() == reflect.Struct && !hasResults && !isUnmarshaler {
+		if isArray {
+			return page, decodeSingle(b, responseBody)
+		}
+		if d.Results != nil {
+			return page, decodeSingle(d.Results, responseBody)
+		}
 	}
 
 	err = json.Unmarshal(b, responseBody)
-	return httpResp, err
+	return page, err
+}
+
+// decodeSingle decodes the only record of a json array in v. An empty array
+// leaves v untouched.
+func decodeSingle(b []byte, v interface{}) error {
+	records := []json.RawMessage{}
+	err := json.Unmarshal(b, &records)
+	if err != nil {
+		return err
+	}
+
+	switch len(records) {
+	case 0:
+		return nil
+	case 1:
+		return json.Unmarshal(records[0], v)
+	}
+	return fmt.Errorf("Expected a single record, got %d", len(records))
+}
+
+// send executes the request and checks the response for errors. Requests are
+// retried according to the retry settings of the client. The caller is
+// responsible for closing the response body.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	req, replayable, err := c.replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		c.configMu.RLock()
+		limiter, beforeRequest := c.limiter, c.beforeRequest
+		c.configMu.RUnlock()
+
+		if m := metricsFromContext(req.Context()); m != nil {
+			atomic.StoreInt64(&m.retries, int64(attempt))
+		}
+
+		if limiter != nil {
+			err := limiter.Wait(req.Context())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if beforeRequest != nil {
+			err := beforeRequest(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var wait time.Duration
+		httpResp, wrote, err := c.sendOnce(req)
+		if err != nil {
+			if !c.shouldRetryError(req, err, wrote, attempt) || !c.canReplay(req, replayable) || !c.takeRetry(req) {
+				return nil, err
+			}
+			_, policy := c.retrySettings()
+			wait = policy.backoff(attempt)
+		} else {
+			var ok bool
+			wait, ok = c.retryWait(httpResp, attempt)
+			if !ok || !c.shouldRetry(req, httpResp, attempt) || !c.canReplay(req, replayable) || !c.takeRetry(req) {
+				// check if the response isn't an error
+				err = CheckResponse(httpResp)
+				return httpResp, err
+			}
+			drainBody(httpResp.Body)
+		}
+
+		err = sleep(req.Context(), wait)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err = rewindBody(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendOnce executes a single attempt of the request. wrote reports if any
+// part of the request was written to the connection.
+func (c *Client) sendOnce(req *http.Request) (httpResp *http.Response, wrote bool, err error) {
+	c.configMu.RLock()
+	debug, limiter, onRequestCompleted := c.debug, c.limiter, c.onRequestCompleted
+	dryRun := c.dryRun
+	c.configMu.RUnlock()
+
+	if dryRun && !isSafeMethod(req.Method) {
+		httpResp, err = c.dryRunResponse(req)
+		return httpResp, false, err
+	}
+
+	if debug == true {
+		c.dumpRequest(req)
+	}
+
+	var written int32
+	trace := &httptrace.ClientTrace{
+		WroteHeaderField: func(key string, value []string) {
+			atomic.StoreInt32(&written, 1)
+		},
+	}
+	traced := req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	metrics := metricsFromContext(req.Context())
+	if metrics != nil && traced.Body != nil && traced.Body != http.NoBody {
+		traced.Body = &countingBody{body: traced.Body, n: &metrics.bytesSent}
+	}
+
+	httpResp, err = c.http.Do(traced)
+	wrote = atomic.LoadInt32(&written) == 1
+	if err != nil {
+		return nil, wrote, err
+	}
+
+	// count the bytes on the wire, before decompressing
+	if metrics != nil {
+		httpResp.Body = &countingBody{body: httpResp.Body, n: &metrics.bytesReceived}
+	}
+
+	err = decompressBody(httpResp)
+	if err != nil {
+		httpResp.Body.Close()
+		return nil, wrote, err
+	}
+	httpResp.Body = &cancelBody{ctx: req.Context(), body: httpResp.Body}
+
+	c.setRateLimit(httpResp)
+	c.setLastResponse(httpResp)
+	if limiter != nil {
+		limiter.Update(ParseRateLimit(httpResp.Header))
+	}
+
+	if onRequestCompleted != nil {
+		onRequestCompleted(req, httpResp)
+	}
+
+	if debug == true {
+		c.dumpResponse(httpResp)
+	}
+
+	return httpResp, wrote, nil
 }
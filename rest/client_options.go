@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+// ClientOption configures a Client created by New. Every option has a setter
+// with the same effect for changing the client afterwards.
+type ClientOption func(*Client) error
+
+// WithBaseURL sets the url of the Exact Online API, see SetBaseURL
+func WithBaseURL(baseURL *url.URL) ClientOption {
+	return func(c *Client) error {
+		return c.SetBaseURL(baseURL)
+	}
+}
+
+// WithDivision sets the division that replaces {division} in request paths
+func WithDivision(division int) ClientOption {
+	return func(c *Client) error {
+		return c.SetDivision(division)
+	}
+}
+
+// WithUserAgent identifies the application in the User-Agent header, see
+// SetUserAgent
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		c.SetUserAgent(userAgent)
+		return nil
+	}
+}
+
+// WithDebug logs every request and response
+func WithDebug(debug bool) ClientOption {
+	return func(c *Client) error {
+		c.SetDebug(debug)
+		return nil
+	}
+}
+
+// WithLogger sets the logger debug output is written to
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) error {
+		c.SetLogger(logger)
+		return nil
+	}
+}
+
+// WithTimeout sets the default timeout of a request, see SetTimeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.SetTimeout(timeout)
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the retry policy for transient failures
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.SetRetryPolicy(policy)
+		return nil
+	}
+}
+
+// WithMaxRetries sets how many times a 429 response is retried
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(c *Client) error {
+		c.SetMaxRetries(maxRetries)
+		return nil
+	}
+}
+
+// WithLimiter sets the limiter that paces the requests of the client
+func WithLimiter(limiter Limiter) ClientOption {
+	return func(c *Client) error {
+		c.SetLimiter(limiter)
+		return nil
+	}
+}
+
+// WithCache enables conditional GET requests, see SetCache
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) error {
+		c.SetCache(cache)
+		return nil
+	}
+}
+
+// WithCompression toggles requesting gzip compressed responses
+func WithCompression(compression bool) ClientOption {
+	return func(c *Client) error {
+		c.SetCompression(compression)
+		return nil
+	}
+}
+
+// WithMetadataLevel sets the OData metadata level of the responses
+func WithMetadataLevel(level MetadataLevel) ClientOption {
+	return func(c *Client) error {
+		c.SetMetadataLevel(level)
+		return nil
+	}
+}
+
+// WithOnRequestCompleted sets a function that's called with every response
+func WithOnRequestCompleted(fn RequestCompletionCallback) ClientOption {
+	return func(c *Client) error {
+		c.SetOnRequestCompleted(fn)
+		return nil
+	}
+}
+
+// WithBeforeRequest sets a function that can modify every request just
+// before it's sent
+func WithBeforeRequest(fn BeforeRequestFunc) ClientOption {
+	return func(c *Client) error {
+		c.SetBeforeRequest(fn)
+		return nil
+	}
+}
+
+// applyOptions applies the options in order and stops at the first error
+func (c *Client) applyOptions(options []ClientOption) error {
+	for _, option := range options {
+		err := option(c)
+		if err != nil {
+			return fmt.Errorf("Invalid client option: %s", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowServer starts a server that writes head and blocks until the request is
+// cancelled
+func slowServer(t *testing.T, head string) (*Client, func()) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if head != "" {
+			w.Write([]byte(head))
+			w.(http.Flusher).Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+
+	baseURL, _ := url.Parse(srv.URL + "/api")
+	c := New(srv.Client())
+	c.SetDivision(1)
+	err := c.SetBaseURL(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, func() {
+		close(done)
+		srv.Close()
+	}
+}
+
+func assertCancelled(t *testing.T, c *Client, responseBody interface{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	req, err := c.NewRequest(ctx, http.MethodGet, "/crm/Accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = c.Do(req, responseBody)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Do returned %s after cancellation", elapsed)
+	}
+}
+
+func TestDoCancelDuringRoundTrip(t *testing.T) {
+	c, stop := slowServer(t, "")
+	defer stop()
+
+	results := []json.RawMessage{}
+	assertCancelled(t, c, &results)
+}
+
+func TestDoCancelDuringDecode(t *testing.T) {
+	c, stop := slowServer(t, `{"d":{"results":[{"ID":"a"},`)
+	defer stop()
+
+	results := []json.RawMessage{}
+	assertCancelled(t, c, &results)
+}
+
+func TestDoCancelDuringRawRead(t *testing.T) {
+	c, stop := slowServer(t, `{"d":{"results":[`)
+	defer stop()
+
+	assertCancelled(t, c, &discard{})
+}
+
+func TestDoCancelledBeforeSend(t *testing.T) {
+	c, stop := slowServer(t, "")
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := c.NewRequest(ctx, http.MethodGet, "/crm/Accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Do(req, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// run with -race: the setters may be called while requests are running
+func TestSettersDuringRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	defer srv.Close()
+
+	baseURL, _ := url.Parse(srv.URL + "/api")
+	c := New(srv.Client())
+	c.SetDivision(1)
+	c.SetLogger(log.New(ioutil.Discard, "", 0))
+	err := c.SetBaseURL(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			c.SetDebug(i%2 == 0)
+			c.SetUserAgent("test")
+			c.SetBaseURL(baseURL)
+			c.SetDivision(1)
+			c.SetOnRequestCompleted(func(*http.Request, *http.Response) {})
+			c.SetTimeout(time.Minute)
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		results := []json.RawMessage{}
+		_, err := c.Get(context.Background(), "/crm/Accounts", &results)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func TestDeduplicateLeaderCancelled(t *testing.T) {
+	received := make(chan struct{}, 2)
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// block the first request until it's cancelled
+		if atomic.AddInt32(&hits, 1) == 1 {
+			received <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[{"ID":"a"}]}}`))
+	}))
+	defer srv.Close()
+
+	baseURL, _ := url.Parse(srv.URL + "/api")
+	c := New(srv.Client())
+	c.SetDivision(1)
+	c.SetDeduplicateGets(true)
+	err := c.SetBaseURL(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.Get(leaderCtx, "/crm/Accounts", &[]json.RawMessage{})
+		leaderErr <- err
+	}()
+	<-received
+
+	waiterErr := make(chan error, 1)
+	results := []json.RawMessage{}
+	go func() {
+		_, err := c.Get(context.Background(), "/crm/Accounts", &results)
+		waiterErr <- err
+	}()
+
+	// give the waiter time to join the request of the leader
+	time.Sleep(50 * time.Millisecond)
+	cancelLeader()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled for the leader, got %v", err)
+	}
+
+	select {
+	case err := <-waiterErr:
+		if err != nil {
+			t.Fatalf("waiter returned error: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter didn't return")
+	}
+
+	if len(results) != 1 {
+		t.Errorf("waiter got %d results, want 1", len(results))
+	}
+	if n := atomic.LoadInt32(&hits); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
+
+func TestNextRequestRelative(t *testing.T) {
+	baseURL, _ := url.Parse("https://start.exactonline.nl/api")
+	c := New(http.DefaultClient)
+	err := c.SetBaseURL(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"https://start.exactonline.nl/api/v1/1/sync/CRM/Accounts?$skiptoken=1L": "https://start.exactonline.nl/api/v1/1/sync/CRM/Accounts?$skiptoken=1L",
+		"/api/v1/1/sync/CRM/Accounts?$skiptoken=1L":                             "https://start.exactonline.nl/api/v1/1/sync/CRM/Accounts?$skiptoken=1L",
+		"v1/1/sync/CRM/Accounts?$skiptoken=guid'a%20b'":                         "https://start.exactonline.nl/api/v1/1/sync/CRM/Accounts?$skiptoken=guid'a%20b'",
+	}
+
+	for next, want := range tests {
+		req, err := c.NextRequest(nil, next)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := req.URL.String(); got != want {
+			t.Errorf("NextRequest(%q): expected %s, got %s", next, want, got)
+		}
+	}
+}
+
+func TestDryRunBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s in dry run mode", r.Method, r.URL)
+	}))
+	defer srv.Close()
+
+	logged := new(bytes.Buffer)
+	baseURL, _ := url.Parse(srv.URL + "/api")
+	c := New(srv.Client())
+	c.SetDivision(1)
+	c.SetDryRun(true)
+	c.SetLogger(log.New(logged, "", 0))
+	err := c.SetBaseURL(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := NewBatchRequest()
+	batch.Add(http.MethodPost, "/v1/{division}/crm/Accounts", map[string]string{"Name": "a"})
+	cs := batch.Changeset()
+	cs.Merge("/v1/{division}/crm/Accounts(guid'b')", map[string]string{"Name": "b"})
+	cs.Delete("/v1/{division}/crm/Accounts(guid'c')")
+
+	results, _, err := c.DoBatch(context.Background(), batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.StatusCode != http.StatusNoContent || result.Err != nil {
+			t.Errorf("result %d: expected 204 without error, got %d %v", i, result.StatusCode, result.Err)
+		}
+	}
+
+	if n := bytes.Count(logged.Bytes(), []byte("Dry run:")); n != 3 {
+		t.Errorf("expected 3 logged operations, got %d:\n%s", n, logged)
+	}
+}
+
+func TestStreamNonJSONResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html>Maintenance</html>"))
+	}))
+	defer srv.Close()
+
+	baseURL, _ := url.Parse(srv.URL + "/api")
+	c := New(srv.Client())
+	c.SetDivision(1)
+	err := c.SetBaseURL(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := c.NewRequest(context.Background(), http.MethodGet, "/v1/{division}/crm/Accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Stream(req, func(record json.RawMessage) error { return nil })
+	nerr, ok := err.(*NonJSONResponseError)
+	if !ok {
+		t.Fatalf("expected *NonJSONResponseError, got %T: %v", err, err)
+	}
+	if string(nerr.Body) != "<html>Maintenance</html>" {
+		t.Errorf("expected the maintenance page in the error, got %q", nerr.Body)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	baseURL, _ := url.Parse(srv.URL + "/api")
+	c := New(srv.Client())
+	c.SetDivision(1)
+	c.SetMaxRetries(3)
+	err := c.SetBaseURL(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := c.NewRequest(context.Background(), http.MethodGet, "/v1/{division}/crm/Accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	httpResp, err := c.Do(req, &[]json.RawMessage{})
+	if err == nil {
+		t.Fatal("expected an error for the 429 response")
+	}
+	if httpResp == nil || httpResp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected the 429 response, got %v", httpResp)
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("expected the 429 to be returned straight away, took %s", d)
+	}
+}
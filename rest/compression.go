@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SetCompression toggles requesting gzip compressed responses. The stdlib
+// transport only decompresses transparently when it added the Accept-Encoding
+// header itself, so responses are decompressed by the client.
+func (c *Client) SetCompression(compression bool) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.compression = compression
+}
+
+// decompressBody replaces the body of a gzip encoded response with a reader
+// that decompresses it
+func decompressBody(httpResp *http.Response) error {
+	if !strings.EqualFold(httpResp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	zr, err := gzip.NewReader(httpResp.Body)
+	if err == io.EOF {
+		// empty body
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	httpResp.Body = &gzipBody{Reader: zr, body: httpResp.Body}
+	httpResp.Header.Del("Content-Encoding")
+	httpResp.Header.Del("Content-Length")
+	httpResp.ContentLength = -1
+	httpResp.Uncompressed = true
+	return nil
+}
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
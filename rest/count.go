@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tim-online/go-exactonline/odata"
+)
+
+// Count returns the number of records of path matching the $filter of opts by
+// requesting path/$count. The other query options are ignored. Use ForDivision
+// to count the records of another division.
+func (c *Client) Count(ctx context.Context, path string, opts *odata.QueryOptions, options ...RequestOption) (int, error) {
+	countOpts := &odata.QueryOptions{}
+	if opts != nil {
+		countOpts.Filter = opts.Filter
+	}
+
+	// {division} is replaced by NewRequest so ForDivision is honored
+	path = strings.TrimSuffix(path, "/") + "/$count"
+	req, err := c.NewRequestWithOptions(ctx, http.MethodGet, path, countOpts, nil, options...)
+	if err != nil {
+		return 0, err
+	}
+
+	// $count responds with a plain text number
+	req.Header.Set("Accept", "text/plain")
+
+	b, _, err := c.RawDo(req)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
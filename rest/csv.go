@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+
+	"github.com/tim-online/go-exactonline/odata"
+)
+
+// ExportCSV requests path with $format=csv and writes the csv body to w as it
+// arrives, without decoding it. The first row holds the column names.
+func (c *Client) ExportCSV(ctx context.Context, path string, opts *odata.QueryOptions, w io.Writer) (*http.Response, error) {
+	req, err := c.newCSVRequest(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, w)
+}
+
+// CSVRows reads the rows of a csv response one at a time
+type CSVRows struct {
+	httpResp *http.Response
+	reader   *csv.Reader
+	cancel   context.CancelFunc
+
+	// reports the metrics of the request on Close
+	done func(*http.Response, error)
+	err  error
+}
+
+// QueryCSV requests path with $format=csv and returns the rows of the
+// response. The caller must call Close when done.
+func (c *Client) QueryCSV(ctx context.Context, path string, opts *odata.QueryOptions) (*CSVRows, error) {
+	req, err := c.newCSVRequest(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, done := c.startMetrics(req)
+	req, cancel := c.withTimeout(req)
+	httpResp, err := c.send(req)
+	if err != nil {
+		if httpResp != nil {
+			httpResp.Body.Close()
+		}
+		cancel()
+		done(httpResp, err)
+		return nil, err
+	}
+
+	reader := csv.NewReader(httpResp.Body)
+	reader.FieldsPerRecord = -1
+
+	return &CSVRows{
+		httpResp: httpResp,
+		reader:   reader,
+		cancel:   cancel,
+		done:     done,
+	}, nil
+}
+
+// Next returns the next row, the column names first. It returns io.EOF after
+// the last row.
+func (r *CSVRows) Next() ([]string, error) {
+	row, err := r.reader.Read()
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return row, err
+}
+
+// Response returns the http response the rows are read from
+func (r *CSVRows) Response() *http.Response {
+	return r.httpResp
+}
+
+// Close closes the response body
+func (r *CSVRows) Close() error {
+	defer r.cancel()
+
+	err := r.httpResp.Body.Close()
+	if r.err == nil {
+		r.err = err
+	}
+	if r.done != nil {
+		r.done(r.httpResp, r.err)
+		r.done = nil
+	}
+	return err
+}
+
+// newCSVRequest creates a GET request for path with $format=csv
+func (c *Client) newCSVRequest(ctx context.Context, path string, opts *odata.QueryOptions) (*http.Request, error) {
+	csvOpts := odata.QueryOptions{}
+	if opts != nil {
+		csvOpts = *opts
+	}
+	csvOpts.Format = "csv"
+
+	return c.NewRequestWithOptions(ctx, http.MethodGet, path, &csvOpts, nil, WithHeader("Accept", "text/csv"))
+}
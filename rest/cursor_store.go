@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"sync"
+)
+
+// CursorStore persists the highest Timestamp synced per entity so a sync can
+// resume where the previous run stopped
+type CursorStore interface {
+	// Load returns the stored timestamp of entity, 0 when there is none
+	Load(entity string) (int64, error)
+	Save(entity string, timestamp int64) error
+}
+
+// NewMemoryCursorStore returns a CursorStore that keeps the timestamps in
+// memory
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{timestamps: map[string]int64{}}
+}
+
+type MemoryCursorStore struct {
+	mu         sync.Mutex
+	timestamps map[string]int64
+}
+
+func (s *MemoryCursorStore) Load(entity string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.timestamps[entity], nil
+}
+
+func (s *MemoryCursorStore) Save(entity string, timestamp int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timestamps[entity] = timestamp
+	return nil
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+)
+
+// headers that are never written to the debug output
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// SetLogger sets the logger debug output is written to. When no logger is set
+// the standard logger of the log package is used.
+func (c *Client) SetLogger(logger *log.Logger) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.logger = logger
+}
+
+// SetDebugRedact toggles scrubbing credentials from the debug output. It is on
+// by default; only turn it off for local debugging.
+func (c *Client) SetDebugRedact(redact bool) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.debugNoRedact = !redact
+}
+
+func (c *Client) logPrintln(v ...interface{}) {
+	c.configMu.RLock()
+	logger := c.logger
+	c.configMu.RUnlock()
+
+	if logger != nil {
+		logger.Println(v...)
+		return
+	}
+	log.Println(v...)
+}
+
+func (c *Client) dumpRequest(req *http.Request) {
+	dump, _ := httputil.DumpRequestOut(req, true)
+	c.logDump(dump)
+}
+
+func (c *Client) dumpResponse(httpResp *http.Response) {
+	dump, _ := httputil.DumpResponse(httpResp, true)
+	c.logDump(dump)
+}
+
+func (c *Client) logDump(dump []byte) {
+	c.configMu.RLock()
+	noRedact := c.debugNoRedact
+	c.configMu.RUnlock()
+
+	if !noRedact {
+		dump = redactDump(dump, redactedHeaders)
+	}
+	c.logPrintln(string(dump))
+}
+
+// redactDump replaces the values of headers in a request or response dump.
+// Only the dump is changed, the request itself is left untouched.
+func redactDump(dump []byte, headers []string) []byte {
+	// headers end at the first empty line
+	end := bytes.Index(dump, []byte("\r\n\r\n"))
+	if end == -1 {
+		end = len(dump)
+	}
+
+	lines := strings.Split(string(dump[:end]), "\r\n")
+	for i, line := range lines {
+		// skip the request or status line
+		if i == 0 {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		for _, h := range headers {
+			if strings.EqualFold(strings.TrimSpace(parts[0]), h) {
+				lines[i] = parts[0] + ": ***"
+				break
+			}
+		}
+	}
+
+	redacted := []byte(strings.Join(lines, "\r\n"))
+	return append(redacted, dump[end:]...)
+}
@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// number of bytes of the body kept in a DecodeError
+const decodeErrorSnippetSize = 512
+
+// ErrDecode is matched by errors.Is for all errors that occur while decoding a
+// response body
+var ErrDecode = errors.New("Could not decode response")
+
+// ErrEmptyBody is returned when a successful response that should be decoded
+// has an empty body. Exact Online sometimes does this during incidents, so the
+// request is usually worth retrying.
+var ErrEmptyBody = errors.New("Empty response body")
+
+// ErrNonJSONResponse is matched by errors.Is when Exact Online responds with
+// something else than json, usually an html page during maintenance
+var ErrNonJSONResponse = errors.New("Response is not json")
+
+// isEmptyBody reports if err is the result of decoding an empty body
+func isEmptyBody(err error, snippet []byte) bool {
+	return (err == io.EOF || err == io.ErrUnexpectedEOF) && len(bytes.TrimSpace(snippet)) == 0
+}
+
+// DecodeError is returned when a response body can't be decoded, for example
+// when Exact Online returns an html maintenance page
+type DecodeError struct {
+	Method string
+	URL    string
+
+	// Body holds the start of the response body
+	Body []byte
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%v %v: %v: %v (body: %q)", e.Method, e.URL, ErrDecode, e.Err, e.Body)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// NonJSONResponseError holds the details of a response with a Content-Type
+// other than json
+type NonJSONResponseError struct {
+	StatusCode  int
+	ContentType string
+
+	// Body holds the start of the response body
+	Body []byte
+}
+
+func (e *NonJSONResponseError) Error() string {
+	return fmt.Sprintf("%v: status %d, Content-Type \"%s\" (body: %q)", ErrNonJSONResponse, e.StatusCode, e.ContentType, e.Body)
+}
+
+func (e *NonJSONResponseError) Is(target error) bool {
+	return target == ErrNonJSONResponse
+}
+
+// nonJSONResponse returns a NonJSONResponseError when the response has a
+// Content-Type that isn't json. A missing Content-Type is accepted.
+func nonJSONResponse(r *http.Response, body io.Reader) error {
+	if r.Header.Get("Content-Type") == "" || checkContentType(r) == nil {
+		return nil
+	}
+
+	snippet, _ := ioutil.ReadAll(io.LimitReader(body, decodeErrorSnippetSize))
+	return &NonJSONResponseError{
+		StatusCode:  r.StatusCode,
+		ContentType: r.Header.Get("Content-Type"),
+		Body:        snippet,
+	}
+}
+
+// snippetReader keeps the first bytes read from r
+type snippetReader struct {
+	r       io.Reader
+	snippet []byte
+}
+
+func newSnippetReader(r io.Reader) *snippetReader {
+	return &snippetReader{r: r}
+}
+
+func (s *snippetReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if room := decodeErrorSnippetSize - len(s.snippet); room > 0 && n > 0 {
+		if n < room {
+			room = n
+		}
+		s.snippet = append(s.snippet, p[:room]...)
+	}
+	return n, err
+}
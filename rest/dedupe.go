@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// SetDeduplicateGets makes concurrent GET requests with the same url and
+// headers share a single round trip: the first request is sent and the others
+// wait for its response. Every caller decodes its own copy of the body. Other
+// methods are never deduplicated.
+func (c *Client) SetDeduplicateGets(dedupe bool) {
+	c.inflightMu.Lock()
+	defer c.inflightMu.Unlock()
+
+	c.dedupe = dedupe
+}
+
+// inflightCall is a GET request that's being sent
+type inflightCall struct {
+	done chan struct{}
+
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+// sendShared works like send but shares the response with concurrent
+// identical GET requests when deduplication is enabled
+func (c *Client) sendShared(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.send(req)
+	}
+
+	c.inflightMu.Lock()
+	if !c.dedupe {
+		c.inflightMu.Unlock()
+		return c.send(req)
+	}
+
+	key := inflightKey(req)
+	if call, ok := c.inflight[key]; ok {
+		c.inflightMu.Unlock()
+
+		// don't wait for the other request after this one is cancelled
+		select {
+		case <-call.done:
+			// the first request was cancelled, not this one: send it again
+			if isContextError(call.err) && req.Context().Err() == nil {
+				return c.sendShared(req)
+			}
+			return call.response(req)
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	call := &inflightCall{done: make(chan struct{})}
+	if c.inflight == nil {
+		c.inflight = map[string]*inflightCall{}
+	}
+	c.inflight[key] = call
+	c.inflightMu.Unlock()
+
+	call.resp, call.err = c.send(req)
+	if call.resp != nil {
+		var err error
+		call.body, err = ioutil.ReadAll(call.resp.Body)
+		call.resp.Body.Close()
+		if call.err == nil {
+			call.err = err
+		}
+	}
+
+	c.inflightMu.Lock()
+	delete(c.inflight, key)
+	c.inflightMu.Unlock()
+	close(call.done)
+
+	return call.response(req)
+}
+
+// response returns a copy of the shared response with its own body
+func (call *inflightCall) response(req *http.Request) (*http.Response, error) {
+	if call.resp == nil {
+		return nil, call.err
+	}
+
+	resp := *call.resp
+	resp.Header = call.resp.Header.Clone()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(call.body))
+	resp.ContentLength = int64(len(call.body))
+	resp.Request = req
+	return &resp, call.err
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// inflightKey identifies a request by its url and headers
+func inflightKey(req *http.Request) string {
+	buf := new(bytes.Buffer)
+	buf.WriteString(req.URL.String())
+	buf.WriteString("\n")
+	req.Header.Write(buf)
+	return buf.String()
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/tim-online/go-exactonline/edm"
+)
+
+// Resolve retrieves a deferred navigation property and decodes it in v. The
+// uri must point to the API the client is configured for.
+func (c *Client) Resolve(ctx context.Context, deferred edm.Deferred, v interface{}) (*http.Response, error) {
+	if deferred.IsEmpty() {
+		return nil, errors.New("Deferred property has no uri")
+	}
+
+	u, err := url.Parse(deferred.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	// don't send credentials to other hosts or over plain http
+	if base := c.BaseURL(); u.Scheme != base.Scheme || u.Host != base.Host {
+		return nil, errors.New("Deferred uri " + deferred.URI + " doesn't point to " + base.Scheme + "://" + base.Host)
+	}
+
+	req, err := c.NewRequest(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, v)
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tim-online/go-exactonline/odata"
+)
+
+const (
+	CurrentMeEndpoint = "/v1/current/Me"
+)
+
+// ErrNoDivision is returned for paths with a {division} placeholder when no
+// division is set, or whose division was already substituted with 0 by
+// SubPath
+var ErrNoDivision = errors.New("Path needs a division but no division is set: use SetDivision")
+
+// divisionSegment matches a substituted division like /v1/123/
+var divisionSegment = regexp.MustCompile(`/v1/(-?[0-9]+)(/|\?|$)`)
+
+// checkDivision returns ErrNoDivision when path needs a division that isn't
+// set and an error for a division that can't exist
+func (c *Client) checkDivision(path string, division int) error {
+	path = c.divisionPath(path)
+	if !strings.Contains(path, "{division}") {
+		// already substituted, e.g. with SubPath
+		m := divisionSegment.FindStringSubmatch(path)
+		if m == nil {
+			return nil
+		}
+
+		var err error
+		division, err = strconv.Atoi(m[1])
+		if err != nil {
+			return fmt.Errorf("Invalid division %s: divisions are positive integers", m[1])
+		}
+	}
+
+	if division == 0 {
+		return ErrNoDivision
+	}
+	return validateDivision(division)
+}
+
+// validateDivision returns an error for zero and negative divisions, which
+// would only result in a 404 from Exact Online
+func validateDivision(division int) error {
+	if division <= 0 {
+		return fmt.Errorf("Invalid division %d: divisions are positive integers", division)
+	}
+	return nil
+}
+
+// SetCacheCurrentDivision makes CurrentDivision remember the division after
+// the first successful call
+func (c *Client) SetCacheCurrentDivision(cache bool) {
+	c.divisionMu.Lock()
+	defer c.divisionMu.Unlock()
+
+	c.cacheCurrentDivision = cache
+	c.currentDivision = 0
+}
+
+// CurrentDivision returns the current division of the authenticated user as
+// reported by current/Me. Use it with SetDivision to bootstrap a new client.
+func (c *Client) CurrentDivision(ctx context.Context) (int, error) {
+	c.divisionMu.Lock()
+	if c.cacheCurrentDivision && c.currentDivision != 0 {
+		division := c.currentDivision
+		c.divisionMu.Unlock()
+		return division, nil
+	}
+	c.divisionMu.Unlock()
+
+	opts := &odata.QueryOptions{Select: []string{"CurrentDivision"}}
+	req, err := c.NewRequestWithOptions(ctx, http.MethodGet, CurrentMeEndpoint, opts, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	me := []struct {
+		CurrentDivision int `json:"CurrentDivision"`
+	}{}
+	_, err = c.Do(req, &me)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(me) == 0 || me[0].CurrentDivision == 0 {
+		return 0, errors.New("No current division in current/Me response")
+	}
+
+	division := me[0].CurrentDivision
+	c.divisionMu.Lock()
+	if c.cacheCurrentDivision {
+		c.currentDivision = division
+	}
+	c.divisionMu.Unlock()
+
+	return division, nil
+}
@@ -0,0 +1,18 @@
+// Package rest implements the http client for the Exact Online REST API.
+//
+// # Tracing
+//
+// Every request carries the context passed to NewRequest, so spans started
+// by an instrumented transport become children of the caller's span. With
+// OpenTelemetry wrap the transport using otelhttp, which starts a span per
+// request, records the method, host, path and status and injects the
+// traceparent header:
+//
+//	httpClient := rest.DefaultHTTPClient()
+//	httpClient.Transport = otelhttp.NewTransport(httpClient.Transport)
+//	client := rest.New(httpClient)
+//
+// Every retry of a request is sent through the transport, so retries show up
+// as separate spans. Without a dependency on otelhttp use SetBeforeRequest to
+// inject headers and SetOnRequestCompleted to record the response status.
+package rest
@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// SetDryRun logs requests that would change data instead of sending them.
+// Everything but GET, HEAD and OPTIONS requests is written to the logger with
+// its method, url and body and answered with an empty 204 No Content response.
+// Reads are still sent so scripts that depend on them keep working. Every
+// operation of a $batch request is logged and answered with a 204 No Content.
+func (c *Client) SetDryRun(dryRun bool) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.dryRun = dryRun
+}
+
+// WithDryRun logs writes instead of sending them, see SetDryRun
+func WithDryRun(dryRun bool) ClientOption {
+	return func(c *Client) error {
+		c.SetDryRun(dryRun)
+		return nil
+	}
+}
+
+// dryRunResponse logs req and returns the response that's used instead of
+// sending it
+func (c *Client) dryRunResponse(req *http.Request) (*http.Response, error) {
+	body := []byte{}
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if strings.HasSuffix(req.URL.Path, "/$batch") {
+		return c.dryRunBatch(req, body)
+	}
+
+	c.logPrintln("Dry run:", req.Method, req.URL.String(), string(bytes.TrimSpace(body)))
+
+	return &http.Response{
+		Status:     "204 No Content",
+		StatusCode: http.StatusNoContent,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+// dryRunBatch logs the operations of a $batch request and returns a batch
+// response with a 204 No Content for each of them
+func (c *Client) dryRunBatch(req *http.Request, body []byte) (*http.Response, error) {
+	reader, err := multipartReader(req.Header.Get("Content-Type"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	err = c.dryRunParts(reader, w)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "multipart/mixed; boundary="+w.Boundary())
+	return &http.Response{
+		Status:        "202 Accepted",
+		StatusCode:    http.StatusAccepted,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(buf),
+		ContentLength: int64(buf.Len()),
+		Request:       req,
+	}, nil
+}
+
+// dryRunParts logs the operations read from reader and writes their responses
+// to w. Changesets are answered with a nested multipart.
+func (c *Client) dryRunParts(reader *multipart.Reader, w *multipart.Writer) error {
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		contentType := p.Header.Get("Content-Type")
+		if strings.HasPrefix(contentType, "multipart/mixed") {
+			csReader, err := multipartReader(contentType, p)
+			if err != nil {
+				return err
+			}
+
+			csBuf := new(bytes.Buffer)
+			csw := multipart.NewWriter(csBuf)
+			err = c.dryRunParts(csReader, csw)
+			if err != nil {
+				return err
+			}
+			err = csw.Close()
+			if err != nil {
+				return err
+			}
+
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Type", "multipart/mixed; boundary="+csw.Boundary())
+			pw, err := w.CreatePart(header)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(pw, csBuf)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		opReq, err := http.ReadRequest(bufio.NewReader(p))
+		if err != nil {
+			return err
+		}
+		opBody, err := ioutil.ReadAll(opReq.Body)
+		if err != nil {
+			return err
+		}
+		c.logPrintln("Dry run:", opReq.Method, opReq.URL.String(), string(bytes.TrimSpace(opBody)))
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "application/http")
+		header.Set("Content-Transfer-Encoding", "binary")
+		pw, err := w.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(pw, "HTTP/1.1 204 No Content\r\n\r\n")
+		if err != nil {
+			return err
+		}
+	}
+}
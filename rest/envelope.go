@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/tim-online/go-exactonline/utils"
+)
+
+// Envelope wraps every response: {"d": ...}. D is an object with results, an
+// array or a single entity.
+type Envelope struct {
+	D utils.JsonTester `json:"d"`
+}
+
+// ErrNoEnvelope is returned in strict envelope mode for a json response
+// without d
+var ErrNoEnvelope = errors.New("Response has no {\"d\": ...} envelope")
+
+// SetStrictEnvelope makes Do fail on responses with other top level keys than
+// d or without d at all, instead of decoding them to an empty result. Only the
+// envelope is checked, unknown fields of the records are still ignored.
+func (c *Client) SetStrictEnvelope(strict bool) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.strictEnvelope = strict
+}
+
+// WithStrictEnvelope enables strict envelope mode, see SetStrictEnvelope
+func WithStrictEnvelope(strict bool) ClientOption {
+	return func(c *Client) error {
+		c.SetStrictEnvelope(strict)
+		return nil
+	}
+}
+
+// D is d when it's an object. Results is nil for a single entity.
+type D struct {
+	Results json.RawMessage `json:"results"`
+	Next    string          `json:"__next"`
+	// __count is a string in the json
+	Count json.Number `json:"__count"`
+}
+
+// Page returns the pagination details of d
+func (d *D) Page() (*Page, error) {
+	page := &Page{Next: d.Next, Count: -1}
+	if d.Count == "" {
+		return page, nil
+	}
+
+	count, err := strconv.Atoi(d.Count.String())
+	if err != nil {
+		return page, err
+	}
+	page.Count = count
+	return page, nil
+}
+
+// DecodeEnvelope decodes a raw response body, for example from RawDo, in v the
+// same way Do does
+func DecodeEnvelope(body []byte, v interface{}) (*Page, error) {
+	return decodeBody(bytes.NewReader(body), v)
+}
@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/tim-online/go-exactonline/odata"
+)
+
+// GetList retrieves all records of path matching opts, following the __next
+// links of every page
+func GetList[T any](ctx context.Context, c *Client, path string, opts *odata.QueryOptions, options ...RequestOption) ([]T, error) {
+	req, err := c.NewRequestWithOptions(ctx, http.MethodGet, path, opts, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []T{}
+	_, err = c.DoAll(req, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetOne retrieves a single entity, e.g. /crm/Accounts(guid'...'). A
+// response with more than one record is an error.
+func GetOne[T any](ctx context.Context, c *Client, path string, options ...RequestOption) (T, error) {
+	var result T
+
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil, options...)
+	if err != nil {
+		return result, err
+	}
+
+	_, err = c.Do(req, &result)
+	return result, err
+}
+
+// Result holds the decoded response of DoResult
+type Result[T any] struct {
+	// Value holds the decoded records: all records for a slice type, the
+	// only record otherwise
+	Value T
+
+	// Found is false when the response had no records
+	Found bool
+
+	Page     *Page
+	Response *http.Response
+}
+
+// DoResult sends req and decodes the response in a T, which is either an
+// entity or a slice of entities. Found tells an empty result apart from an
+// error, so lookups don't have to check the zero value. For an entity T a
+// response with more than one record is an error.
+func DoResult[T any](c *Client, req *http.Request) (Result[T], error) {
+	result := Result[T]{}
+
+	if t := reflect.TypeOf(result.Value); t != nil && t.Kind() == reflect.Slice {
+		page, httpResp, err := c.DoPage(req, &result.Value)
+		result.Page, result.Response = page, httpResp
+		result.Found = err == nil && reflect.ValueOf(result.Value).Len() > 0
+		return result, err
+	}
+
+	records := []T{}
+	page, httpResp, err := c.DoPage(req, &records)
+	result.Page, result.Response = page, httpResp
+	if err != nil {
+		return result, err
+	}
+
+	switch len(records) {
+	case 0:
+		return result, nil
+	case 1:
+		result.Value = records[0]
+		result.Found = true
+		return result, nil
+	}
+	return result, fmt.Errorf("Expected a single record, got %d", len(records))
+}
@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tim-online/go-exactonline/edm"
+	"github.com/tim-online/go-exactonline/filter"
+)
+
+// EntityPath returns the path of the entity with key in entitySet, e.g.
+// /crm/Accounts(guid'00000000-0000-0000-0000-000000000000')
+func EntityPath(entitySet string, key edm.GUID) string {
+	return strings.TrimSuffix(entitySet, "/") + "(" + key.Literal() + ")"
+}
+
+// EntityPathWithIntKey returns the path of an entity keyed on an integer,
+// e.g. /payroll/Employees(12)
+func EntityPathWithIntKey(entitySet string, key int) string {
+	return strings.TrimSuffix(entitySet, "/") + "(" + strconv.Itoa(key) + ")"
+}
+
+// EntityPathWithCompositeKey returns the path of an entity keyed on multiple
+// properties, e.g. /sync/Item(Division=1,ID=guid'...'). The values are
+// formatted with filter.Literal and the properties sorted by name.
+func EntityPathWithCompositeKey(entitySet string, key map[string]interface{}) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + filter.Literal(key[name])
+	}
+	return strings.TrimSuffix(entitySet, "/") + "(" + strings.Join(parts, ",") + ")"
+}
+
+// DeleteByKey deletes the entity with key from entitySet, e.g.
+// DeleteByKey(ctx, "/crm/Accounts", id)
+func (c *Client) DeleteByKey(ctx context.Context, entitySet string, key edm.GUID, options ...RequestOption) (*http.Response, error) {
+	return c.Delete(ctx, EntityPath(entitySet, key), options...)
+}
+
+// DeleteByIntKey deletes the entity keyed on an integer from entitySet
+func (c *Client) DeleteByIntKey(ctx context.Context, entitySet string, key int, options ...RequestOption) (*http.Response, error) {
+	return c.Delete(ctx, EntityPathWithIntKey(entitySet, key), options...)
+}
+
+// DeleteByCompositeKey deletes the entity keyed on multiple properties from
+// entitySet
+func (c *Client) DeleteByCompositeKey(ctx context.Context, entitySet string, key map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	return c.Delete(ctx, EntityPathWithCompositeKey(entitySet, key), options...)
+}
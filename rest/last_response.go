@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"net/http"
+)
+
+// LastResponse returns the status and headers of the last response the client
+// received, or nil before the first request. The body isn't available: it
+// belongs to the caller of the request. When the client is used from multiple
+// goroutines the last response can belong to any of them, use the
+// *http.Response returned by Do to inspect a specific request.
+func (c *Client) LastResponse() *http.Response {
+	c.lastResponseMu.Lock()
+	defer c.lastResponseMu.Unlock()
+
+	if c.lastResponse == nil {
+		return nil
+	}
+
+	resp := *c.lastResponse
+	resp.Header = c.lastResponse.Header.Clone()
+	return &resp
+}
+
+func (c *Client) setLastResponse(httpResp *http.Response) {
+	resp := *httpResp
+	resp.Header = httpResp.Header.Clone()
+	resp.Body = http.NoBody
+
+	c.lastResponseMu.Lock()
+	defer c.lastResponseMu.Unlock()
+	c.lastResponse = &resp
+}
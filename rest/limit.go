@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EffectiveTop returns the number of records Exact Online returns for $top
+// on path: a $top above PageSize(path), 60 for regular and 1000 for /bulk/
+// and /sync/ endpoints, is silently clamped. Use DoLimit to retrieve more
+// records than fit on a page.
+func EffectiveTop(path string, top int) int {
+	if size := PageSize(path); top > size {
+		return size
+	}
+	return top
+}
+
+// DoLimit works like DoAll but stops once limit records are retrieved. A
+// $top of req that's larger than a page is removed so the records are
+// retrieved over multiple pages instead of being clamped. Records already in
+// the slice pointed to by v don't count towards limit. A limit of 0 doesn't
+// send the request at all.
+func (c *Client) DoLimit(req *http.Request, v interface{}, limit int) (*http.Response, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("DoLimit expects a pointer to a slice")
+	}
+	slice := rv.Elem()
+
+	if limit < 0 {
+		return nil, fmt.Errorf("Invalid limit %d: should be 0 or more", limit)
+	}
+	if limit == 0 {
+		return nil, nil
+	}
+
+	if top, ok := queryTop(req.URL.RawQuery); ok && top > PageSize(req.URL.Path) {
+		r := req.Clone(req.Context())
+		r.URL.RawQuery = removeQueryParam(req.URL.RawQuery, "$top")
+		req = r
+	}
+
+	start := slice.Len()
+	return c.followPages(req, func(req *http.Request) (string, *http.Response, error) {
+		results := reflect.New(slice.Type())
+		page, httpResp, err := c.DoPage(req, results.Interface())
+		if err != nil {
+			return "", httpResp, err
+		}
+
+		slice.Set(reflect.AppendSlice(slice, results.Elem()))
+
+		if slice.Len()-start >= limit {
+			slice.Set(slice.Slice(0, start+limit))
+			return "", httpResp, nil
+		}
+		return page.Next, httpResp, nil
+	})
+}
+
+// queryTop returns the $top value of a raw query
+func queryTop(rawQuery string) (int, bool) {
+	for _, pair := range strings.Split(rawQuery, "&") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 && (kv[0] == "$top" || kv[0] == "%24top") {
+			top, err := strconv.Atoi(kv[1])
+			return top, err == nil
+		}
+	}
+	return 0, false
+}
+
+// removeQueryParam removes key from a raw query and keeps the encoding of the
+// other parameters
+func removeQueryParam(rawQuery string, key string) string {
+	pairs := []string{}
+	for _, pair := range strings.Split(rawQuery, "&") {
+		k := strings.SplitN(pair, "=", 2)[0]
+		if k == key || k == strings.Replace(key, "$", "%24", 1) {
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter paces requests before they're sent. Wait is called before every
+// attempt and Update with the rate limits of every response.
+type Limiter interface {
+	Wait(ctx context.Context) error
+	Update(RateLimit)
+}
+
+// SetLimiter sets the limiter that paces the requests of the client. Use nil
+// to disable pacing.
+func (c *Client) SetLimiter(limiter Limiter) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.limiter = limiter
+}
+
+// NewAdaptiveLimiter returns a Limiter that spreads the remaining calls of the
+// minutely window evenly over the time left until the window resets. When
+// only reserve calls are left it waits for the reset. Without rate limit
+// headers requests aren't delayed.
+func NewAdaptiveLimiter(reserve int) *AdaptiveLimiter {
+	return &AdaptiveLimiter{reserve: reserve}
+}
+
+type AdaptiveLimiter struct {
+	reserve int
+
+	mu     sync.Mutex
+	window RateLimitWindow
+	next   time.Time
+}
+
+// maximum time to wait for a window reset, protects against clock skew
+const maxLimiterWait = time.Minute
+
+func (l *AdaptiveLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve1()
+	if wait <= 0 {
+		return nil
+	}
+	return sleep(ctx, wait)
+}
+
+// reserve1 reserves a call and returns how long to wait for it
+func (l *AdaptiveLimiter) reserve1() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	w := l.window
+	if !w.Valid || w.Reset.IsZero() || !now.Before(w.Reset) {
+		return 0
+	}
+
+	var at time.Time
+	if w.Remaining <= l.reserve {
+		// budget is spent: wait for the reset
+		at = w.Reset
+	} else {
+		interval := w.Reset.Sub(now) / time.Duration(w.Remaining-l.reserve)
+		at = l.next
+		if at.Before(now) {
+			at = now
+		}
+		l.next = at.Add(interval)
+		l.window.Remaining--
+	}
+
+	wait := at.Sub(now)
+	if wait > maxLimiterWait {
+		wait = maxLimiterWait
+	}
+	return wait
+}
+
+func (l *AdaptiveLimiter) Update(rateLimit RateLimit) {
+	if !rateLimit.Minutely.Valid {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.window = rateLimit.Minutely
+}
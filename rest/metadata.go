@@ -0,0 +1,36 @@
+package rest
+
+// MetadataLevel controls how much OData metadata Exact Online includes in
+// responses via the odata parameter of the Accept header
+type MetadataLevel string
+
+const (
+	// MetadataDefault sends a plain application/json Accept header
+	MetadataDefault MetadataLevel = ""
+	// MetadataVerbose includes __metadata and __deferred for every record
+	MetadataVerbose MetadataLevel = "verbose"
+	// MetadataNone leaves out __metadata and __deferred, which shrinks large
+	// (sync) responses. edm.MetaData and edm.Deferred fields stay empty.
+	MetadataNone MetadataLevel = "nometadata"
+)
+
+// SetMetadataLevel sets the metadata level requested for every request. The
+// default keeps the metadata so deferred navigation properties can be
+// resolved.
+func (c *Client) SetMetadataLevel(level MetadataLevel) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.metadataLevel = level
+}
+
+// acceptHeader returns the Accept header for json responses
+func (c *Client) acceptHeader() string {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+
+	if c.metadataLevel == MetadataDefault {
+		return mediaType
+	}
+	return mediaType + ";odata=" + string(c.metadataLevel)
+}
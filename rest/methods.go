@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// MethodMerge is the OData verb for partial updates: only the properties
+	// present in the body are changed, where PUT replaces the entity
+	MethodMerge = "MERGE"
+)
+
+// Get sends a GET request for path and decodes the response in responseBody
+func (c *Client) Get(ctx context.Context, path string, responseBody interface{}, options ...RequestOption) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, responseBody)
+}
+
+// Post sends body in a POST request for path and decodes the response in
+// responseBody. Use WithReturnRepresentation to get the created entity back.
+func (c *Client) Post(ctx context.Context, path string, body interface{}, responseBody interface{}, options ...RequestOption) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodPost, path, body, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, responseBody)
+}
+
+// Delete sends a DELETE request for path. The response body isn't decoded.
+func (c *Client) Delete(ctx context.Context, path string, options ...RequestOption) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodDelete, path, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, nil)
+}
+
+// Put sends body in a PUT request for path and decodes the response in
+// responseBody
+func (c *Client) Put(ctx context.Context, path string, body interface{}, responseBody interface{}, options ...RequestOption) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodPut, path, body, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, responseBody)
+}
+
+// Merge sends body in a MERGE request for path. The response body isn't
+// decoded.
+func (c *Client) Merge(ctx context.Context, path string, body interface{}, options ...RequestOption) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, MethodMerge, path, body, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, nil)
+}
@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// RequestMetrics describes a request sent with Do, DoPage, DoBatch, QueryCSV
+// or any of the other methods built on them. Every page of DoAll and Stream is
+// a request of its own. Retries are included in the totals.
+type RequestMetrics struct {
+	Method string
+	URL    string
+
+	// Wall-clock time from sending until the response body is closed
+	Duration time.Duration
+
+	// Bytes of the request bodies sent and the (compressed) response bodies
+	// read, retries included
+	BytesSent     int64
+	BytesReceived int64
+
+	// Number of attempts after the first one
+	Retries int
+
+	// Status code of the final response, 0 when there was none
+	StatusCode int
+
+	// Error returned to the caller
+	Err error
+}
+
+// MetricsHook is called with the metrics of every completed request
+type MetricsHook func(RequestMetrics)
+
+// SetMetricsHook sets a function that's called after every request with its
+// duration, byte counts, retries and final status. Use nil to remove it.
+func (c *Client) SetMetricsHook(fn MetricsHook) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.metricsHook = fn
+}
+
+// WithMetricsHook reports the metrics of every request, see SetMetricsHook
+func WithMetricsHook(fn MetricsHook) ClientOption {
+	return func(c *Client) error {
+		c.SetMetricsHook(fn)
+		return nil
+	}
+}
+
+type metricsKey struct{}
+
+// requestMetrics keeps the counters of a request while it's being sent
+type requestMetrics struct {
+	start         time.Time
+	bytesSent     int64
+	bytesReceived int64
+	retries       int64
+}
+
+// startMetrics adds the metric counters to the context of req when a metrics
+// hook is set. done reports the metrics of the completed request.
+func (c *Client) startMetrics(req *http.Request) (r *http.Request, done func(*http.Response, error)) {
+	c.configMu.RLock()
+	hook := c.metricsHook
+	c.configMu.RUnlock()
+
+	if hook == nil {
+		return req, func(*http.Response, error) {}
+	}
+
+	m := &requestMetrics{start: time.Now()}
+	req = req.WithContext(context.WithValue(req.Context(), metricsKey{}, m))
+	return req, func(httpResp *http.Response, err error) {
+		metrics := RequestMetrics{
+			Method:        req.Method,
+			URL:           req.URL.String(),
+			Duration:      time.Since(m.start),
+			BytesSent:     atomic.LoadInt64(&m.bytesSent),
+			BytesReceived: atomic.LoadInt64(&m.bytesReceived),
+			Retries:       int(atomic.LoadInt64(&m.retries)),
+			Err:           err,
+		}
+		if httpResp != nil {
+			metrics.StatusCode = httpResp.StatusCode
+		}
+		hook(metrics)
+	}
+}
+
+// metricsFromContext returns the counters of the request, nil when no metrics
+// hook is set
+func metricsFromContext(ctx context.Context) *requestMetrics {
+	m, _ := ctx.Value(metricsKey{}).(*requestMetrics)
+	return m
+}
+
+// countingBody counts the bytes read from body in n
+type countingBody struct {
+	body io.ReadCloser
+	n    *int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	atomic.AddInt64(b.n, int64(n))
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	return b.body.Close()
+}
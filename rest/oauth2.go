@@ -0,0 +1,214 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenStore persists the oauth2 tokens used by TokenTransport. Exact Online
+// rotates the refresh token on every refresh: once a token is passed to
+// SaveToken the previous refresh token can't be used anymore, so
+// implementations must make sure the saved token is stored durably.
+type TokenStore interface {
+	Token() (*oauth2.Token, error)
+	SaveToken(*oauth2.Token) error
+}
+
+// NewMemoryTokenStore returns a TokenStore that keeps the token in memory
+func NewMemoryTokenStore(token *oauth2.Token) *MemoryTokenStore {
+	return &MemoryTokenStore{token: token}
+}
+
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (s *MemoryTokenStore) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return nil, errors.New("No token in store")
+	}
+	return s.token, nil
+}
+
+func (s *MemoryTokenStore) SaveToken(token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+	return nil
+}
+
+// NewTokenTransport returns a transport that authorizes requests with the
+// access token from store and refreshes it via the token endpoint of config.
+// When base is nil http.DefaultTransport is used.
+func NewTokenTransport(config *oauth2.Config, store TokenStore, base http.RoundTripper) *TokenTransport {
+	return &TokenTransport{
+		Config: config,
+		Store:  store,
+		Base:   base,
+	}
+}
+
+// TokenTransport is an http.RoundTripper that adds the Authorization header
+// to every request. Expired access tokens are refreshed before sending and a
+// 401 response triggers a single refresh and retry of the original request,
+// unless the WWW-Authenticate header says the token isn't expired. When the
+// refresh token is rejected the error matches ErrInvalidGrant.
+type TokenTransport struct {
+	Config *oauth2.Config
+	Store  TokenStore
+	Base   http.RoundTripper
+
+	// guards token and makes sure only one refresh runs at a time so a
+	// rotated refresh token is never used twice
+	mu    sync.Mutex
+	token *oauth2.Token
+
+	// token couldn't be saved after the last refresh
+	unsaved bool
+}
+
+func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.validToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base().RoundTrip(authorize(req, token))
+	if err != nil || !tokenExpired(resp) {
+		return resp, err
+	}
+
+	// the body is consumed and can't be sent again
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	token, err = t.refreshToken(req.Context(), token)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+
+	retry := authorize(req, token)
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t.base().RoundTrip(retry)
+}
+
+func (t *TokenTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// validToken returns the current access token, refreshing it when expired
+func (t *TokenTransport) validToken(ctx context.Context) (*oauth2.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token, err := t.currentToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Valid() {
+		return token, nil
+	}
+
+	return t.refresh(ctx, token)
+}
+
+// refreshToken refreshes the token after a 401. When another request already
+// refreshed the rejected token in the meantime, that token is used instead.
+func (t *TokenTransport) refreshToken(ctx context.Context, rejected *oauth2.Token) (*oauth2.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token, err := t.currentToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken != rejected.AccessToken && token.Valid() {
+		return token, nil
+	}
+
+	return t.refresh(ctx, token)
+}
+
+// currentToken returns the token in memory or loads it from the store. Must be
+// called with t.mu held.
+func (t *TokenTransport) currentToken() (*oauth2.Token, error) {
+	if t.token != nil {
+		// retry saving a refreshed token that didn't make it to the store
+		if t.unsaved && t.Store.SaveToken(t.token) == nil {
+			t.unsaved = false
+		}
+		return t.token, nil
+	}
+
+	token, err := t.Store.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errors.New("No token in store")
+	}
+
+	t.token = token
+	return token, nil
+}
+
+// refresh exchanges the refresh token for a new token and saves it. Must be
+// called with t.mu held.
+func (t *TokenTransport) refresh(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	if token.RefreshToken == "" {
+		return nil, errors.New("No refresh token available")
+	}
+
+	// a token without access token forces the token source to refresh
+	src := t.Config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken})
+	newToken, err := src.Token()
+	if err != nil {
+		return nil, tokenError(err)
+	}
+
+	if newToken.RefreshToken == "" {
+		newToken.RefreshToken = token.RefreshToken
+	}
+
+	// keep the new token in memory first: the old refresh token is invalid
+	// now, so it may never be lost even when saving fails
+	t.token = newToken
+
+	err = t.Store.SaveToken(newToken)
+	if err != nil {
+		t.unsaved = true
+		return nil, err
+	}
+
+	return newToken, nil
+}
+
+// authorize returns a copy of req with the Authorization header set
+func authorize(req *http.Request, token *oauth2.Token) *http.Request {
+	r := req.Clone(req.Context())
+	token.SetAuthHeader(r)
+	return r
+}
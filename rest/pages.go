@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Pages retrieves the pages of req in the background, following the __next
+// links, and sends the results array of every page on the first channel. The
+// next page is only requested after the previous one is received. Both
+// channels are closed when all pages are sent, a request fails or ctx is
+// cancelled; the error channel receives the error, if any, first.
+func (c *Client) Pages(ctx context.Context, req *http.Request) (<-chan json.RawMessage, <-chan error) {
+	pages := make(chan json.RawMessage)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(pages)
+		defer close(errs)
+
+		_, err := c.followPages(req.WithContext(ctx), func(req *http.Request) (string, *http.Response, error) {
+			var results json.RawMessage
+			page, httpResp, err := c.DoPage(req, &results)
+			if err != nil {
+				return "", httpResp, err
+			}
+
+			select {
+			case pages <- results:
+			case <-ctx.Done():
+				return "", httpResp, ctx.Err()
+			}
+			return page.Next, httpResp, nil
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return pages, errs
+}
@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// DoAll sends an API request and keeps following the __next links Exact Online
+// returns until all pages are retrieved. The results of every page are appended
+// to the slice pointed to by v. This works the same for /bulk/ endpoints, which
+// return up to MaxBulkPageSize records per page.
+func (c *Client) DoAll(req *http.Request, v interface{}) (*http.Response, error) {
+	return c.DoAllWithCapacity(req, v, 0)
+}
+
+// DoAllWithCapacity works like DoAll but first makes room for capacity more
+// records in the slice pointed to by v, for example the result of Count. This
+// saves growing and copying the slice over and over on large syncs. The
+// capacity is only a hint: more records are appended all the same.
+func (c *Client) DoAllWithCapacity(req *http.Request, v interface{}, capacity int) (*http.Response, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("DoAll expects a pointer to a slice")
+	}
+	slice := rv.Elem()
+
+	if capacity > 0 && slice.Cap()-slice.Len() < capacity {
+		grown := reflect.MakeSlice(slice.Type(), slice.Len(), slice.Len()+capacity)
+		reflect.Copy(grown, slice)
+		slice.Set(grown)
+	}
+
+	return c.followPages(req, func(req *http.Request) (string, *http.Response, error) {
+		results := reflect.New(slice.Type())
+		page, httpResp, err := c.DoPage(req, results.Interface())
+		if err != nil {
+			return "", httpResp, err
+		}
+
+		slice.Set(reflect.AppendSlice(slice, results.Elem()))
+		return page.Next, httpResp, nil
+	})
+}
+
+// followPages calls fetch for req and then for the __next url it returns until
+// there's no next page or fetch fails. fetch returns an empty next url to stop
+// early. The request context is checked between pages.
+func (c *Client) followPages(req *http.Request, fetch func(*http.Request) (string, *http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	for {
+		// stop when the request context is cancelled between pages
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		next, httpResp, err := fetch(req)
+		if err != nil || next == "" {
+			return httpResp, err
+		}
+
+		req, err = c.nextRequest(req, next)
+		if err != nil {
+			return httpResp, err
+		}
+	}
+}
+
+// NextRequest creates a GET request for the __next url of a previous page.
+// The url is used as is, so skiptokens keep their original encoding. Relative
+// urls are resolved against the base url.
+func (c *Client) NextRequest(ctx context.Context, next string) (*http.Request, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// keep the base path when resolving relative urls
+	base := *c.BaseURL()
+	base.Path = base.Path + "/"
+
+	req, err := newNextRequest(ctx, &base, next)
+	if err != nil {
+		return nil, err
+	}
+
+	c.addHeaders(req)
+	return req, nil
+}
+
+// SkipToken returns the $skiptoken value of a __next url
+func SkipToken(next string) (string, error) {
+	u, err := url.Parse(next)
+	if err != nil {
+		return "", err
+	}
+
+	// don't use u.Query(): that would decode the other parameters as well
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		key, err := url.QueryUnescape(kv[0])
+		if err != nil || key != "$skiptoken" {
+			continue
+		}
+
+		// + is kept as is, Exact encodes spaces as %20
+		return url.PathUnescape(kv[1])
+	}
+
+	return "", nil
+}
+
+// nextRequest creates a GET request for the __next url with the headers of
+// the previous request
+func (c *Client) nextRequest(req *http.Request, next string) (*http.Request, error) {
+	// resolve relative __next urls against the previous page
+	nextReq, err := newNextRequest(req.Context(), req.URL, next)
+	if err != nil {
+		return nil, err
+	}
+
+	nextReq.Header = req.Header.Clone()
+	return nextReq, nil
+}
+
+// newNextRequest creates a GET request for the __next url resolved against
+// base
+func newNextRequest(ctx context.Context, base *url.URL, next string) (*http.Request, error) {
+	u, err := url.Parse(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.NewRequestWithContext(ctx, http.MethodGet, base.ResolveReference(u).String(), nil)
+}
@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit holds the rate limits Exact Online reports on every response
+type RateLimit struct {
+	// Daily limit of the app per division
+	Daily RateLimitWindow
+
+	// Minutely limit of the app per division
+	Minutely RateLimitWindow
+}
+
+type RateLimitWindow struct {
+	// Maximum number of calls in the window
+	Limit int
+
+	// Number of calls left in the window
+	Remaining int
+
+	// Moment the window resets
+	Reset time.Time
+
+	// Valid is false when the response didn't report this window. Limit and
+	// Remaining are meaningless in that case.
+	Valid bool
+}
+
+// ParseRateLimit reads the X-RateLimit headers from a response header
+func ParseRateLimit(header http.Header) RateLimit {
+	return RateLimit{
+		Daily:    parseRateLimitWindow(header, "X-RateLimit-"),
+		Minutely: parseRateLimitWindow(header, "X-RateLimit-Minutely-"),
+	}
+}
+
+func parseRateLimitWindow(header http.Header, prefix string) RateLimitWindow {
+	window := RateLimitWindow{}
+
+	limit, err := strconv.Atoi(header.Get(prefix + "Limit"))
+	if err != nil {
+		return window
+	}
+
+	remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+	if err != nil {
+		return window
+	}
+
+	window.Limit = limit
+	window.Remaining = remaining
+	window.Valid = true
+
+	// reset is in milliseconds since epoch
+	milis, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64)
+	if err == nil {
+		window.Reset = time.Unix(0, milis*int64(time.Millisecond))
+	}
+
+	return window
+}
+
+// LastRateLimit returns the rate limits reported by the most recent response
+func (c *Client) LastRateLimit() RateLimit {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	return c.rateLimit
+}
+
+func (c *Client) setRateLimit(httpResp *http.Response) {
+	rateLimit := ParseRateLimit(httpResp.Header)
+
+	// keep the previous limits when the response doesn't report any
+	if !rateLimit.Daily.Valid && !rateLimit.Minutely.Valid {
+		return
+	}
+
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	c.rateLimit = rateLimit
+}
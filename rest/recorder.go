@@ -0,0 +1,175 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Interaction is a recorded request and response. Request headers aren't
+// recorded so credentials don't end up in the cassette.
+type Interaction struct {
+	Key        string      `json:"key"`
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// NewRecorderTransport returns a transport that sends requests with base and
+// writes every request and response to the cassette file at path. When base
+// is nil http.DefaultTransport is used.
+func NewRecorderTransport(path string, base http.RoundTripper) *RecorderTransport {
+	return &RecorderTransport{Path: path, Base: base}
+}
+
+type RecorderTransport struct {
+	Path string
+	Base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+func (t *RecorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.interactions = append(t.interactions, Interaction{
+		Key:        interactionKey(req, reqBody),
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	})
+
+	b, err := json.MarshalIndent(t.interactions, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(t.Path, b, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// NewReplayTransport returns a transport that responds with the interactions
+// of the cassette file at path instead of sending the requests. Requests are
+// matched on method, url and body. Identical requests get the recorded
+// responses in order, the last one is repeated.
+func NewReplayTransport(path string) (*ReplayTransport, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	interactions := []Interaction{}
+	err = json.Unmarshal(b, &interactions)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ReplayTransport{
+		interactions: map[string][]Interaction{},
+		served:       map[string]int{},
+	}
+	for _, i := range interactions {
+		t.interactions[i.Key] = append(t.interactions[i.Key], i)
+	}
+	return t, nil
+}
+
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	served       map[string]int
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := interactionKey(req, reqBody)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	recorded := t.interactions[key]
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("No recorded response for %s %s", req.Method, req.URL)
+	}
+
+	n := t.served[key]
+	if n >= len(recorded) {
+		n = len(recorded) - 1
+	}
+	t.served[key] = n + 1
+	i := recorded[n]
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.StatusCode, http.StatusText(i.StatusCode)),
+		StatusCode:    i.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        i.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(i.Body)),
+		ContentLength: int64(len(i.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody reads the body of req and replaces it with a copy
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// interactionKey identifies a request by method, url and a hash of the body
+func interactionKey(req *http.Request, body []byte) string {
+	hash := sha256.Sum256(body)
+	return req.Method + " " + req.URL.String() + " " + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// maximum number of redirects followed, the same as the stdlib
+const maxRedirects = 10
+
+// the regional hosts of Exact Online
+var exactOnlineHostPattern = regexp.MustCompile(`^start\.exactonline\.(nl|be|de|fr|es|com|co\.uk)$`)
+
+// IsExactOnlineHost reports if host is one of the regional Exact Online hosts
+// like start.exactonline.nl or start.exactonline.co.uk
+func IsExactOnlineHost(host string) bool {
+	return exactOnlineHostPattern.MatchString(strings.ToLower(host))
+}
+
+// CheckRedirect is an http.Client CheckRedirect func that follows redirects
+// between the regional Exact Online hosts. The stdlib drops the Authorization
+// header when a redirect changes the host, this keeps it as long as both
+// hosts belong to Exact Online and the redirect uses https. Bodies are sent
+// again through GetBody, which NewRequest sets for json bodies.
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("Stopped after 10 redirects")
+	}
+
+	first := via[0]
+	if req.Header.Get("Authorization") != "" || first.Header.Get("Authorization") == "" {
+		return nil
+	}
+
+	if req.URL.Scheme != "https" {
+		return nil
+	}
+
+	if !IsExactOnlineHost(req.URL.Hostname()) || !IsExactOnlineHost(first.URL.Hostname()) {
+		return nil
+	}
+
+	req.Header.Set("Authorization", first.Header.Get("Authorization"))
+	return nil
+}
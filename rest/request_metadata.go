@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"context"
+)
+
+// RequestMetadata holds values that identify the operation a request belongs
+// to, like the entity or the sync run. It's carried by the request context so
+// hooks like the RequestCompletionCallback can read it from req.Context().
+type RequestMetadata map[string]string
+
+type requestMetadataKey struct{}
+
+// WithRequestMetadata returns a copy of ctx carrying md. Values already in
+// ctx are kept unless md overrides them.
+func WithRequestMetadata(ctx context.Context, md RequestMetadata) context.Context {
+	merged := RequestMetadata{}
+	for k, v := range RequestMetadataFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range md {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, requestMetadataKey{}, merged)
+}
+
+// RequestMetadataFromContext returns the metadata stored in ctx by
+// WithRequestMetadata, nil when there is none. The map must not be changed.
+func RequestMetadataFromContext(ctx context.Context) RequestMetadata {
+	if ctx == nil {
+		return nil
+	}
+	md, _ := ctx.Value(requestMetadataKey{}).(RequestMetadata)
+	return md
+}
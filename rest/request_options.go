@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// RequestOption changes a single request created by NewRequest. Options are
+// applied after the default headers so they can override them.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	header http.Header
+
+	// odata.maxpagesize preference, 0 when not set
+	maxPageSize int
+
+	// division of the request, 0 for the client division
+	division int
+}
+
+// WithHeader sets header key to value on the request, replacing the default
+// value if any
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithContentType sets the Content-Type of the request body, e.g. for a
+// multipart body or an io.Reader
+func WithContentType(contentType string) RequestOption {
+	return WithHeader("Content-Type", contentType)
+}
+
+// WithIfMatch makes an update conditional on the etag of the entity, e.g.
+// edm.MetaData.ETag. When the entity changed in the meantime Exact Online
+// rejects the request and Do returns an error matching ErrPreconditionFailed.
+func WithIfMatch(etag string) RequestOption {
+	return WithHeader("If-Match", etag)
+}
+
+// WithReturnRepresentation asks Exact Online to return the created or updated
+// entity, including the values assigned by the server like the ID, so it can
+// be decoded without fetching it again
+func WithReturnRepresentation() RequestOption {
+	return WithHeader("Prefer", "return=representation")
+}
+
+// WithReturnMinimal asks Exact Online to respond without a body
+func WithReturnMinimal() RequestOption {
+	return WithHeader("Prefer", "return=minimal")
+}
+
+// ForDivision sends the request to division instead of the division of the
+// client, so a single client can be shared by goroutines working on
+// different divisions. Use WithDivision to set the division of the client.
+func ForDivision(division int) RequestOption {
+	return func(o *requestOptions) {
+		o.division = division
+	}
+}
+
+// WithMaxPageSize asks Exact Online for pages of at most size records with
+// Prefer: odata.maxpagesize. The size is clamped to PageSize of the endpoint.
+// The preference is kept when following __next links.
+func WithMaxPageSize(size int) RequestOption {
+	return func(o *requestOptions) {
+		o.maxPageSize = size
+	}
+}
+
+func newRequestOptions(options []RequestOption) *requestOptions {
+	o := &requestOptions{header: http.Header{}}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// apply applies the request options to req
+func (o *requestOptions) apply(req *http.Request) {
+	for key, values := range o.header {
+		req.Header[key] = values
+	}
+
+	if o.maxPageSize > 0 {
+		size := EffectiveTop(req.URL.Path, o.maxPageSize)
+		prefer := "odata.maxpagesize=" + strconv.Itoa(size)
+		if p := req.Header.Get("Prefer"); p != "" {
+			prefer = p + ", " + prefer
+		}
+		req.Header.Set("Prefer", prefer)
+	}
+}
@@ -1,7 +1,9 @@
 package rest
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -10,7 +12,7 @@ import (
 
 // CheckResponse checks the API response for errors, and returns them if present. A response is considered an
 // error if it has a status code outside the 200 range. API error responses are expected to have either no response
-// body, or a XML response body that maps to ErrorResponse. Any other response body will be silently ignored.
+// body, or a JSON response body that maps to ErrorResponse. Any other response body is used as the error message.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; c >= 200 && c <= 299 {
 		// status code is ok: no errors
@@ -18,14 +20,7 @@ func CheckResponse(r *http.Response) error {
 	}
 
 	// create base error response
-	errorResponse := &ErrorResponse{Response: r}
-
-	// check if content type is json
-	err := checkContentType(r)
-	if err != nil {
-		errorResponse.Message.Value = err.Error()
-		return errorResponse
-	}
+	errorResponse := &ErrorResponse{Response: r, StatusCode: r.StatusCode}
 
 	// read response body
 	data, err := ioutil.ReadAll(r.Body)
@@ -38,6 +33,14 @@ func CheckResponse(r *http.Response) error {
 		return errorResponse
 	}
 
+	// check if content type is json
+	err = checkContentType(r)
+	if err != nil {
+		errorResponse.Message.Value = strings.TrimSpace(string(data))
+		errorResponse.Err = nonJSONResponse(r, bytes.NewReader(data))
+		return errorResponse
+	}
+
 	type Envelope struct {
 		Error *ErrorResponse `json:"error"`
 	}
@@ -46,7 +49,8 @@ func CheckResponse(r *http.Response) error {
 	envelope := &Envelope{Error: errorResponse}
 	err = json.Unmarshal(data, envelope)
 	if err != nil {
-		errorResponse.Message.Value = fmt.Sprintf("Malformed json response")
+		// malformed json: use the raw body
+		errorResponse.Message.Value = strings.TrimSpace(string(data))
 		return errorResponse
 	}
 
@@ -59,19 +63,41 @@ func CheckResponse(r *http.Response) error {
 // 		"message": {
 // 			"lang": "",
 // 			"value": "Can't delete: Account 58 - Used in: Administrations"
+// 		},
+// 		"innererror": {
+// 			"message": "",
+// 			"type": "",
+// 			"stacktrace": "",
+// 			"internalexception": {}
 // 		}
 // 	}
 // }
 
+// ErrPreconditionFailed is matched by errors.Is for 412 Precondition Failed
+// responses: the entity changed since the etag passed with WithIfMatch
+var ErrPreconditionFailed = errors.New("Precondition failed")
+
+// ErrConflict is matched by errors.Is for 409 Conflict responses
+var ErrConflict = errors.New("Conflict")
+
 type ErrorResponse struct {
 	// HTTP response that caused this error
-	Response *http.Response
+	Response *http.Response `json:"-"`
 
 	// HTTP status code
-	Code string
+	StatusCode int `json:"-"`
+
+	// Exact Online error code
+	Code string `json:"code"`
 
 	// Fault message
 	Message ErrorMessage `json:"message"`
+
+	// Details of the server side exception, when included
+	InnerError *InnerError `json:"innererror"`
+
+	// NonJSONResponseError when the error wasn't returned as json
+	Err error `json:"-"`
 }
 
 type ErrorMessage struct {
@@ -79,9 +105,59 @@ type ErrorMessage struct {
 	Value string `json:"value"`
 }
 
+type InnerError struct {
+	Message           string      `json:"message"`
+	Type              string      `json:"type"`
+	StackTrace        string      `json:"stacktrace"`
+	InternalException *InnerError `json:"internalexception"`
+}
+
+// IsValidation reports if the request was rejected with 400 Bad Request,
+// which Exact Online uses for invalid or missing property values
+func (r *ErrorResponse) IsValidation() bool {
+	return r.StatusCode == http.StatusBadRequest
+}
+
+// Validation splits a validation message like "Mandatory: Journal" in the
+// reason and the field it applies to. The field is the label of the property
+// in the CustomDescriptionLanguage, not the property name. ok is false when
+// this isn't a validation error or the message has another form.
+func (r *ErrorResponse) Validation() (reason string, field string, ok bool) {
+	if !r.IsValidation() {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(r.Message.Value, ": ", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), true
+}
+
 func (r *ErrorResponse) Error() string {
+	message := r.Message.Value
+	if r.Code != "" {
+		message = fmt.Sprintf("%s: %s", r.Code, message)
+	}
+
 	return fmt.Sprintf("%v %v: %d (%v)",
-		r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, r.Message)
+		r.Response.Request.Method, r.Response.Request.URL, r.StatusCode, message)
+}
+
+func (r *ErrorResponse) Unwrap() error {
+	return r.Err
+}
+
+func (r *ErrorResponse) Is(target error) bool {
+	switch target {
+	case ErrPreconditionFailed:
+		return r.StatusCode == http.StatusPreconditionFailed
+	case ErrConflict:
+		return r.StatusCode == http.StatusConflict
+	case ErrTokenExpired:
+		return r.Response != nil && tokenExpired(r.Response)
+	}
+	return false
 }
 
 func checkContentType(response *http.Response) error {
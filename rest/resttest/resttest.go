@@ -0,0 +1,106 @@
+// Package resttest provides helpers for testing code that uses the Exact
+// Online REST client against a local test server.
+package resttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/tim-online/go-exactonline/rest"
+)
+
+const (
+	// Division is the division of clients created by NewClient
+	Division = 1
+)
+
+// NewClient starts a test server with handler and returns a client pointed at
+// it. The server is listening on /api, like the Exact Online API, and must be
+// closed by the caller.
+func NewClient(handler http.Handler) (*rest.Client, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+
+	baseURL, err := url.Parse(srv.URL + "/api")
+	if err != nil {
+		srv.Close()
+		panic(err)
+	}
+
+	c := rest.New(srv.Client())
+	err = c.SetBaseURL(baseURL)
+	if err != nil {
+		srv.Close()
+		panic(err)
+	}
+	c.SetDivision(Division)
+
+	return c, srv
+}
+
+// Envelope returns results wrapped in the envelope of a collection:
+// {"d":{"results":[...]}}
+func Envelope(results interface{}) []byte {
+	return PageEnvelope(results, "")
+}
+
+// PageEnvelope returns results wrapped in the envelope of a collection with a
+// __next url pointing to the next page
+func PageEnvelope(results interface{}, next string) []byte {
+	d := struct {
+		Results interface{} `json:"results"`
+		Next    string      `json:"__next,omitempty"`
+	}{
+		Results: results,
+		Next:    next,
+	}
+
+	return marshal(map[string]interface{}{"d": d})
+}
+
+// EntityEnvelope returns entity wrapped in the envelope of a single entity:
+// {"d":{...}}
+func EntityEnvelope(entity interface{}) []byte {
+	return marshal(map[string]interface{}{"d": entity})
+}
+
+// ResultsHandler returns a handler that responds with results wrapped in the
+// collection envelope
+func ResultsHandler(results interface{}) http.HandlerFunc {
+	body := Envelope(results)
+	return func(w http.ResponseWriter, r *http.Request) {
+		Write(w, http.StatusOK, body)
+	}
+}
+
+// ErrorHandler returns a handler that responds with an Exact Online error
+func ErrorHandler(status int, message string) http.HandlerFunc {
+	body := marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code": "",
+			"message": map[string]string{
+				"lang":  "",
+				"value": message,
+			},
+		},
+	})
+	return func(w http.ResponseWriter, r *http.Request) {
+		Write(w, status, body)
+	}
+}
+
+// Write writes a JSON response
+func Write(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func marshal(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
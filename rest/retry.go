@@ -0,0 +1,213 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// backoff used when a 429 response has no Retry-After header
+	retryMinBackoff = 1 * time.Second
+	retryMaxBackoff = 30 * time.Second
+
+	// longest Retry-After of a 429 that is waited for, enough for the
+	// minutely limit to reset
+	retryMaxWait = 1 * time.Minute
+)
+
+// SetMaxRetries sets how many times a request is retried after a 429 Too Many
+// Requests response. The default of 0 disables retries. A 429 with a
+// Retry-After of more than a minute, e.g. after hitting the daily limit, is
+// returned instead of retried.
+func (c *Client) SetMaxRetries(maxRetries int) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.maxRetries = maxRetries
+}
+
+// RetryPolicy configures retries of transient failures: 500, 502, 503 and 504
+// responses and connection errors. Responses are only retried for GET, HEAD
+// and OPTIONS requests. Other requests are only retried after a connection
+// error when nothing of the request was written yet, so a write is never
+// applied twice.
+type RetryPolicy struct {
+	MaxRetries int
+
+	// Exponential backoff with jitter between MinBackoff and MaxBackoff.
+	// Defaults to 1s and 30s. A response with a Retry-After of more than
+	// MaxBackoff is returned instead of retried.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// SetRetryPolicy sets the retry policy for transient failures. The zero
+// RetryPolicy disables these retries.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.retryPolicy = policy
+}
+
+// retrySettings returns the max retries after a 429 and the retry policy
+func (c *Client) retrySettings() (int, RetryPolicy) {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+
+	return c.maxRetries, c.retryPolicy
+}
+
+// backoff returns the backoff duration for the attempt with full jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = retryMinBackoff
+	}
+	if max <= 0 {
+		max = retryMaxBackoff
+	}
+
+	d := min
+	for i := 0; i < attempt && d < max; i++ {
+		d = d * 2
+	}
+	if d > max {
+		d = max
+	}
+
+	// wait between d/2 and d
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// shouldRetry reports if the response is worth retrying
+func (c *Client) shouldRetry(req *http.Request, httpResp *http.Response, attempt int) bool {
+	maxRetries, policy := c.retrySettings()
+
+	switch httpResp.StatusCode {
+	case http.StatusTooManyRequests:
+		return attempt < maxRetries
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return attempt < policy.MaxRetries && isSafeMethod(req.Method)
+	}
+	return false
+}
+
+// shouldRetryError reports if a connection error is worth retrying. wrote is
+// true when (part of) the request was written to the connection.
+func (c *Client) shouldRetryError(req *http.Request, err error, wrote bool, attempt int) bool {
+	_, policy := c.retrySettings()
+	if attempt >= policy.MaxRetries {
+		return false
+	}
+
+	// cancelled or timed out by the caller
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	return isSafeMethod(req.Method) || !wrote
+}
+
+// retryWait returns how long to wait before retrying the response. ok is
+// false when the Retry-After header asks to wait longer than the maximum.
+func (c *Client) retryWait(httpResp *http.Response, attempt int) (wait time.Duration, ok bool) {
+	if httpResp.StatusCode == http.StatusTooManyRequests {
+		wait = retryAfter(httpResp, backoff(attempt))
+		return wait, wait <= retryMaxWait
+	}
+
+	_, policy := c.retrySettings()
+	max := policy.MaxBackoff
+	if max <= 0 {
+		max = retryMaxBackoff
+	}
+	wait = retryAfter(httpResp, policy.backoff(attempt))
+	return wait, wait <= max
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// retryAfter returns how long to wait before the next attempt. The Retry-After
+// header is honored when present, otherwise fallback is used.
+func retryAfter(httpResp *http.Response, fallback time.Duration) time.Duration {
+	header := httpResp.Header.Get("Retry-After")
+	if header != "" {
+		// Retry-After: 120
+		if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+
+		// Retry-After: Fri, 31 Dec 1999 23:59:59 GMT
+		if t, err := http.ParseTime(header); err == nil {
+			d := time.Until(t)
+			if d < 0 {
+				return 0
+			}
+			return d
+		}
+	}
+
+	return fallback
+}
+
+// backoff returns the exponential backoff duration for the attempt
+func backoff(attempt int) time.Duration {
+	d := retryMinBackoff
+	for i := 0; i < attempt; i++ {
+		d = d * 2
+		if d >= retryMaxBackoff {
+			return retryMaxBackoff
+		}
+	}
+	return d
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rewindBody returns a copy of req with a fresh body so it can be sent again
+func rewindBody(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errors.New("Request body can't be rewound for a retry")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// drainBody reads the remainder of the body and closes it so the underlying
+// connection can be reused
+func drainBody(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, body)
+	body.Close()
+}
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// SetRetryBufferSize sets how many bytes of a request body that can't be
+// rewound, like a streamed io.Reader, are buffered in memory so the request can
+// be retried. Larger bodies are streamed and their requests aren't retried.
+// The default of 0 never buffers.
+func (c *Client) SetRetryBufferSize(size int64) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.retryBufferSize = size
+}
+
+// WithRetryBufferSize buffers streamed bodies up to size bytes for retries,
+// see SetRetryBufferSize
+func WithRetryBufferSize(size int64) ClientOption {
+	return func(c *Client) error {
+		c.SetRetryBufferSize(size)
+		return nil
+	}
+}
+
+// replayableBody makes sure the body of req can be sent again on a retry. A
+// body without GetBody is buffered when it fits in the retry buffer. ok is
+// false when the body can't be replayed.
+func (c *Client) replayableBody(req *http.Request) (r *http.Request, ok bool, err error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req, true, nil
+	}
+
+	maxRetries, policy := c.retrySettings()
+	c.configMu.RLock()
+	size := c.retryBufferSize
+	c.configMu.RUnlock()
+
+	// nothing will be retried, don't buffer for nothing
+	if maxRetries <= 0 && policy.MaxRetries <= 0 {
+		return req, true, nil
+	}
+
+	if size <= 0 {
+		return req, false, nil
+	}
+
+	// read one byte more to find out if the body fits
+	buf, err := ioutil.ReadAll(io.LimitReader(req.Body, size+1))
+	if err != nil {
+		req.Body.Close()
+		return nil, false, err
+	}
+
+	r = req.Clone(req.Context())
+	if int64(len(buf)) > size {
+		// too large: send what's read followed by the rest of the stream
+		r.Body = &multiReadCloser{
+			Reader: io.MultiReader(bytes.NewReader(buf), req.Body),
+			closer: req.Body,
+		}
+		return r, false, nil
+	}
+
+	req.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf)), nil
+	}
+	if r.ContentLength <= 0 {
+		r.ContentLength = int64(len(buf))
+	}
+	return r, true, nil
+}
+
+// canReplay reports if the body of a request that's about to be retried can
+// be sent again and logs a warning when it can't
+func (c *Client) canReplay(req *http.Request, replayable bool) bool {
+	if !replayable {
+		c.logPrintln("Warning: not retrying", req.Method, req.URL.String()+":",
+			"the request body can't be rewound, see SetRetryBufferSize")
+	}
+	return replayable
+}
+
+type multiReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (r *multiReadCloser) Close() error {
+	return r.closer.Close()
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// RetryBudget caps the total number of retries of all requests sharing it,
+// e.g. across the workers of a sync run. Once the budget is spent failed
+// requests return their error right away instead of being retried. A
+// RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	mu        sync.Mutex
+	remaining int
+}
+
+// NewRetryBudget returns a budget of retries retries
+func NewRetryBudget(retries int) *RetryBudget {
+	return &RetryBudget{remaining: retries}
+}
+
+// Remaining returns the number of retries left
+func (b *RetryBudget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining
+}
+
+// take spends a retry, it returns false when the budget is exhausted
+func (b *RetryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remaining <= 0 {
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+type retryBudgetKey struct{}
+
+// ContextWithRetryBudget returns a copy of ctx carrying budget. The requests
+// made with the context spend the budget instead of the one of the client.
+func ContextWithRetryBudget(ctx context.Context, budget *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, budget)
+}
+
+// SetRetryBudget sets the budget spent by the retries of all requests of the
+// client. Use nil to retry without a budget.
+func (c *Client) SetRetryBudget(budget *RetryBudget) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.retryBudget = budget
+}
+
+// WithRetryBudget sets the budget spent by the retries of all requests
+func WithRetryBudget(budget *RetryBudget) ClientOption {
+	return func(c *Client) error {
+		c.SetRetryBudget(budget)
+		return nil
+	}
+}
+
+// takeRetry spends a retry of the budget of the request context or the
+// client. Without a budget retries are always allowed.
+func (c *Client) takeRetry(req *http.Request) bool {
+	budget, _ := req.Context().Value(retryBudgetKey{}).(*RetryBudget)
+	if budget == nil {
+		c.configMu.RLock()
+		budget = c.retryBudget
+		c.configMu.RUnlock()
+	}
+
+	if budget == nil {
+		return true
+	}
+	return budget.take()
+}
@@ -0,0 +1,236 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StreamFunc is called by Stream for every record
+type StreamFunc func(record json.RawMessage) error
+
+// Stream sends an API request and calls fn for every record in the results
+// while the response is read, following the __next links until all pages are
+// retrieved. Unlike DoAll the records aren't kept in memory. Streaming stops
+// at the first error returned by fn.
+func (c *Client) Stream(req *http.Request, fn StreamFunc) (*http.Response, error) {
+	return c.followPages(req, func(req *http.Request) (string, *http.Response, error) {
+		return c.streamPage(req, fn)
+	})
+}
+
+// streamPage streams the records of a single page and returns the __next url
+func (c *Client) streamPage(req *http.Request, fn StreamFunc) (string, *http.Response, error) {
+	req, done := c.startMetrics(req)
+	next, httpResp, err := c.doStreamPage(req, fn)
+	done(httpResp, err)
+	return next, httpResp, err
+}
+
+func (c *Client) doStreamPage(req *http.Request, fn StreamFunc) (string, *http.Response, error) {
+	req, cancel := c.withTimeout(req)
+	defer cancel()
+
+	httpResp, err := c.send(req)
+	if httpResp == nil {
+		return "", nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if err != nil {
+		return "", httpResp, err
+	}
+
+	// nothing to decode
+	if httpResp.StatusCode == http.StatusNoContent {
+		return "", httpResp, nil
+	}
+
+	if httpResp.ContentLength == 0 {
+		return "", httpResp, ErrEmptyBody
+	}
+
+	// don't try to decode maintenance pages
+	err = nonJSONResponse(httpResp, httpResp.Body)
+	if err != nil {
+		return "", httpResp, err
+	}
+
+	body := newSnippetReader(httpResp.Body)
+	next, err := streamEnvelope(json.NewDecoder(body), fn)
+	if serr, ok := err.(*streamError); ok {
+		return "", httpResp, serr.err
+	}
+	if isEmptyBody(err, body.snippet) {
+		return "", httpResp, ErrEmptyBody
+	}
+	if err != nil && req.Context().Err() != nil {
+		return "", httpResp, req.Context().Err()
+	}
+	if err != nil {
+		err = &DecodeError{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Body:   body.snippet,
+			Err:    err,
+		}
+	}
+	return next, httpResp, err
+}
+
+// streamError wraps errors returned by the StreamFunc so they aren't reported
+// as decode errors
+type streamError struct {
+	err error
+}
+
+func (e *streamError) Error() string {
+	return e.err.Error()
+}
+
+// streamEnvelope reads the {"d": ...} envelope and calls fn for every record
+func streamEnvelope(dec *json.Decoder, fn StreamFunc) (string, error) {
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return "", err
+	}
+
+	next := ""
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+
+		if key != "d" {
+			err = skipValue(dec)
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		next, err = streamD(dec, fn)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return next, expectDelim(dec, '}')
+}
+
+// streamD reads d: an array of records, an object with results or a single
+// entity
+func streamD(dec *json.Decoder, fn StreamFunc) (string, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+
+	switch t {
+	case json.Delim('['):
+		return "", streamArray(dec, fn)
+	case json.Delim('{'):
+	default:
+		return "", fmt.Errorf("Unexpected %v in envelope", t)
+	}
+
+	// keep the fields in case d is a single entity instead of results
+	next := ""
+	hasResults := false
+	entity := new(bytes.Buffer)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		key, _ := t.(string)
+
+		switch key {
+		case "results":
+			hasResults = true
+			err = expectDelim(dec, '[')
+			if err != nil {
+				return "", err
+			}
+			err = streamArray(dec, fn)
+		case "__next":
+			err = dec.Decode(&next)
+		default:
+			raw := json.RawMessage{}
+			err = dec.Decode(&raw)
+			if err == nil {
+				if entity.Len() > 0 {
+					entity.WriteByte(',')
+				}
+				k, _ := json.Marshal(key)
+				entity.Write(k)
+				entity.WriteByte(':')
+				entity.Write(raw)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = expectDelim(dec, '}')
+	if err != nil {
+		return "", err
+	}
+
+	if !hasResults {
+		record := append(append([]byte("{"), entity.Bytes()...), '}')
+		err = callStreamFunc(fn, record)
+	}
+	return next, err
+}
+
+// streamArray calls fn for every element of an array. The opening [ must be
+// read already.
+func streamArray(dec *json.Decoder, fn StreamFunc) error {
+	for dec.More() {
+		record := json.RawMessage{}
+		err := dec.Decode(&record)
+		if err != nil {
+			return err
+		}
+
+		err = callStreamFunc(fn, record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func callStreamFunc(fn StreamFunc, record json.RawMessage) error {
+	err := fn(record)
+	if err != nil {
+		return &streamError{err: err}
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+
+	if t != delim {
+		return fmt.Errorf("Expected %v, got %v", delim, t)
+	}
+	return nil
+}
+
+func skipValue(dec *json.Decoder) error {
+	raw := json.RawMessage{}
+	return dec.Decode(&raw)
+}
@@ -0,0 +1,178 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/tim-online/go-exactonline/edm"
+	"github.com/tim-online/go-exactonline/odata"
+)
+
+// NewSyncIterator returns an iterator over the rows of a /sync/ endpoint that
+// changed after timestamp. Use 0 to start a full sync.
+func (c *Client) NewSyncIterator(path string, opts *odata.QueryOptions, timestamp int64) *SyncIterator {
+	return &SyncIterator{
+		client:    c,
+		path:      path,
+		opts:      opts,
+		timestamp: timestamp,
+	}
+}
+
+// NewStoredSyncIterator returns a sync iterator that resumes from the
+// timestamp of entity in store. The timestamp of a page is saved on the next
+// call to Next, so only once the caller processed the page: after a crash
+// the last page is synced again instead of skipped. Call Commit to save the
+// timestamp of the last page when stopping early.
+func (c *Client) NewStoredSyncIterator(store CursorStore, entity string, path string, opts *odata.QueryOptions) (*SyncIterator, error) {
+	timestamp, err := store.Load(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	it := c.NewSyncIterator(path, opts, timestamp)
+	it.store = store
+	it.entity = entity
+	it.committed = timestamp
+	return it, nil
+}
+
+// SyncIterator pages through a /sync/ endpoint with $filter=Timestamp gt n and
+// keeps track of the highest Timestamp seen. Persist Timestamp() after a sync
+// and pass it to NewSyncIterator to resume incrementally on the next run.
+type SyncIterator struct {
+	client *Client
+	path   string
+	opts   *odata.QueryOptions
+
+	timestamp int64
+	next      string
+	done      bool
+
+	// persists the timestamp of processed pages
+	store     CursorStore
+	entity    string
+	committed int64
+}
+
+// syncRow is used to read the Timestamp of every row
+type syncRow struct {
+	Timestamp json.Number `json:"Timestamp"`
+}
+
+// Next retrieves the next page of rows in the slice pointed to by v. It
+// returns false when there are no more pages.
+func (it *SyncIterator) Next(ctx context.Context, v interface{}) (bool, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return false, errors.New("SyncIterator expects a pointer to a slice")
+	}
+
+	// the previous page is processed by now
+	err := it.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	if it.done {
+		return false, nil
+	}
+
+	req, err := it.request(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	rows := []json.RawMessage{}
+	page, _, err := it.client.DoPage(req, &rows)
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := it.timestamp
+	for _, row := range rows {
+		r := syncRow{}
+		err = json.Unmarshal(row, &r)
+		if err != nil {
+			return false, err
+		}
+
+		if r.Timestamp == "" {
+			return false, errors.New("Sync row has no Timestamp")
+		}
+
+		ts, err := r.Timestamp.Int64()
+		if err != nil {
+			return false, err
+		}
+
+		if ts > timestamp {
+			timestamp = ts
+		}
+	}
+
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return false, err
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return false, err
+	}
+
+	// only move the timestamp forward once the page is decoded
+	it.timestamp = timestamp
+	it.next = page.Next
+	it.done = page.Next == ""
+	return len(rows) > 0 || !it.done, nil
+}
+
+// Commit saves the timestamp of the pages returned so far in the cursor store
+// of the iterator. Next commits automatically before retrieving a page.
+func (it *SyncIterator) Commit() error {
+	if it.store == nil || it.timestamp == it.committed {
+		return nil
+	}
+
+	err := it.store.Save(it.entity, it.timestamp)
+	if err != nil {
+		return err
+	}
+	it.committed = it.timestamp
+	return nil
+}
+
+// SyncFilter returns the $filter for the rows of a /sync/ endpoint that changed
+// after timestamp: Timestamp gt 12345L
+func SyncFilter(timestamp int64) string {
+	return "Timestamp gt " + edm.Int64Literal(timestamp)
+}
+
+// Timestamp returns the highest Timestamp seen so far
+func (it *SyncIterator) Timestamp() int64 {
+	return it.timestamp
+}
+
+// request creates the request for the first page or follows the __next url
+func (it *SyncIterator) request(ctx context.Context) (*http.Request, error) {
+	if it.next != "" {
+		return it.client.NextRequest(ctx, it.next)
+	}
+
+	opts := odata.QueryOptions{}
+	if it.opts != nil {
+		opts = *it.opts
+	}
+
+	filter := SyncFilter(it.timestamp)
+	if opts.Filter != "" {
+		filter = "(" + opts.Filter + ") and " + filter
+	}
+	opts.Filter = filter
+
+	return it.client.NewRequestWithOptions(ctx, http.MethodGet, it.path, &opts, nil)
+}
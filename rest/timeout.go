@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// SetTimeout sets the default timeout of a request, including reading the
+// response body. It's only used when the request context has no deadline.
+// DoAll applies the timeout to every page. Use 0 to disable the timeout.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+
+	c.timeout = timeout
+}
+
+// withTimeout returns req with the default timeout of the client. The cancel
+// func must be called when the response is handled.
+func (c *Client) withTimeout(req *http.Request) (*http.Request, context.CancelFunc) {
+	c.configMu.RLock()
+	timeout := c.timeout
+	c.configMu.RUnlock()
+
+	if timeout <= 0 {
+		return req, func() {}
+	}
+
+	if _, ok := req.Context().Deadline(); ok {
+		return req, func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	return req.WithContext(ctx), cancel
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrTokenExpired is matched by errors.Is for 401 responses to an expired
+	// access token. Refreshing the token and retrying the request fixes it.
+	ErrTokenExpired = errors.New("Access token expired")
+
+	// ErrInvalidGrant is matched by errors.Is when the token endpoint rejects
+	// the refresh token. The user has to authorize the app again.
+	ErrInvalidGrant = errors.New("Invalid grant")
+)
+
+// TokenError is returned by TokenTransport when refreshing the token failed
+// with an oauth2 error response
+type TokenError struct {
+	// oauth2 error code, e.g. invalid_grant
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+
+	Err error `json:"-"`
+}
+
+func (e *TokenError) Error() string {
+	if e.Description != "" {
+		return "Refreshing token failed: " + e.Code + " (" + e.Description + ")"
+	}
+	return "Refreshing token failed: " + e.Code
+}
+
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TokenError) Is(target error) bool {
+	return target == ErrInvalidGrant && e.Code == "invalid_grant"
+}
+
+// tokenError converts the error body of the token endpoint in a TokenError
+func tokenError(err error) error {
+	retrieveErr, ok := err.(*oauth2.RetrieveError)
+	if !ok {
+		return err
+	}
+
+	tokenErr := &TokenError{Err: err}
+	if json.Unmarshal(retrieveErr.Body, tokenErr) != nil || tokenErr.Code == "" {
+		return err
+	}
+	return tokenErr
+}
+
+// tokenExpired reports if a 401 response was caused by an expired access
+// token. Without a WWW-Authenticate error that's assumed to be the case.
+func tokenExpired(r *http.Response) bool {
+	if r.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	code := authenticateError(r.Header.Get("WWW-Authenticate"))
+	return code == "" || code == "invalid_token"
+}
+
+// authenticateError returns the error parameter of a WWW-Authenticate header:
+// Bearer error="invalid_token", error_description="The access token expired"
+func authenticateError(header string) string {
+	for _, param := range strings.Split(header, ",") {
+		param = strings.TrimSpace(param)
+		if i := strings.Index(param, " "); i >= 0 && !strings.Contains(param[:i], "=") {
+			// strip the auth scheme
+			param = strings.TrimSpace(param[i:])
+		}
+
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == "error" {
+			return strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		}
+	}
+	return ""
+}
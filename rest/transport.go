@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// DefaultHTTPClient returns an http client tuned for many requests to a single
+// host. Unlike http.DefaultClient it doesn't share a global transport. To
+// authorize requests use DefaultTransport as the base of a TokenTransport.
+// Redirects between the regional Exact Online hosts keep their Authorization
+// header, see CheckRedirect.
+func DefaultHTTPClient() *http.Client {
+	return &http.Client{
+		Transport:     DefaultTransport(),
+		CheckRedirect: CheckRedirect,
+	}
+}
+
+// DefaultTransport returns the transport used by DefaultHTTPClient
+func DefaultTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 2 * time.Minute,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
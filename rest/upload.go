@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+)
+
+// Upload posts content as a multipart/form-data file upload to path, together
+// with the form fields. The file is streamed, so it's never loaded in memory
+// completely. Requests with a streamed body are only retried when the body fits
+// in the retry buffer, see SetRetryBufferSize.
+func (c *Client) Upload(ctx context.Context, path, fileName string, content io.Reader, fields map[string]string) (*http.Response, error) {
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeUpload(w, fileName, content, fields))
+	}()
+
+	req, err := c.NewRequest(ctx, http.MethodPost, path, pr, WithContentType(w.FormDataContentType()))
+	if err != nil {
+		pr.CloseWithError(err)
+		return nil, err
+	}
+
+	httpResp, err := c.Do(req, nil)
+
+	// stop the writer when the request ended before the body was sent
+	pr.CloseWithError(errors.New("Upload request finished"))
+	return httpResp, err
+}
+
+// writeUpload writes the form fields and the file to w
+func writeUpload(w *multipart.Writer, fileName string, content io.Reader, fields map[string]string) error {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		err := w.WriteField(k, fields[k])
+		if err != nil {
+			return err
+		}
+	}
+
+	part, err := w.CreateFormFile("file", fileName)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, content)
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
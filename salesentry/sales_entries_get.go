@@ -19,7 +19,7 @@ import (
 func (s *Service) SalesEntriesGet(requestParams *SalesEntriesGetParams, ctx context.Context) (*SalesEntriesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewSalesEntriesGetResponse()
-	path := s.rest.SubPath(SalesEntriesEndpoint)
+	path := SalesEntriesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
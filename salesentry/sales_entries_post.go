@@ -12,7 +12,7 @@ import (
 func (s *Service) SalesEntriesPost(body *SalesEntriesPostBody, ctx context.Context) (*SalesEntriesPostResponse, error) {
 	method := http.MethodPost
 	responseBody := s.NewSalesEntriesPostResponse()
-	path := s.rest.SubPath(SalesEntriesEndpoint)
+	path := SalesEntriesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, body)
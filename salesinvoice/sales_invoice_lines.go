@@ -20,7 +20,7 @@ const (
 func (s *Service) SalesInvoiceLinesGet(requestParams *SalesInvoiceLinesGetParams, ctx context.Context) (*SalesInvoiceLinesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewSalesInvoiceLinesGetResponse()
-	path := s.rest.SubPath(SalesInvoiceLinesEndpoint)
+	path := SalesInvoiceLinesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -65,7 +65,7 @@ type SalesInvoiceLinesGetParams struct {
 func (s *Service) SalesInvoiceLinesPost(body *SalesInvoiceLinesPostBody, ctx context.Context) (*SalesInvoiceLinesPostResponse, error) {
 	method := http.MethodPost
 	responseBody := s.NewSalesInvoiceLinesPostResponse()
-	path := s.rest.SubPath(SalesInvoiceLinesEndpoint)
+	path := SalesInvoiceLinesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, body)
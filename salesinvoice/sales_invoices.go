@@ -20,7 +20,7 @@ const (
 func (s *Service) SalesInvoicesGet(requestParams *SalesInvoicesGetParams, ctx context.Context) (*SalesInvoicesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewSalesInvoicesGetResponse()
-	path := s.rest.SubPath(SalesInvoicesEndpoint)
+	path := SalesInvoicesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -68,7 +68,7 @@ type SalesInvoicesGetParams struct {
 func (s *Service) SalesInvoicesPost(body *SalesInvoicesPostBody, ctx context.Context) (*SalesInvoicesPostResponse, error) {
 	method := http.MethodPost
 	responseBody := s.NewSalesInvoicesPostResponse()
-	path := s.rest.SubPath(SalesInvoicesEndpoint)
+	path := SalesInvoicesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, body)
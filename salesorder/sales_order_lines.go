@@ -18,7 +18,7 @@ const (
 func (s *Service) SalesOrderLinesGet(requestParams *SalesOrderLinesGetParams, ctx context.Context) (*SalesOrderLinesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewSalesOrderLinesGetResponse()
-	path := s.rest.SubPath(SalesOrderLinesEndpoint)
+	path := SalesOrderLinesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -18,7 +18,7 @@ const (
 func (s *Service) SalesOrdersGet(requestParams *SalesOrdersGetParams, ctx context.Context) (*SalesOrdersGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewSalesOrdersGetResponse()
-	path := s.rest.SubPath(SalesOrdersEndpoint)
+	path := SalesOrdersEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
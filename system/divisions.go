@@ -20,7 +20,7 @@ const (
 func (s *Service) DivisionsGet(requestParams *DivisionsGetParams, ctx context.Context) (*DivisionsGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewDivisionsGetResponse()
-	path := s.rest.SubPath(DivisionsEndpoint)
+	path := DivisionsEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
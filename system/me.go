@@ -20,7 +20,7 @@ const (
 func (s *Service) MeGet(requestParams *MeGetParams, ctx context.Context) (*MeGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewMeGetResponse()
-	path := s.rest.SubPath(MeEndpoint)
+	path := MeEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
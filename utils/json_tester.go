@@ -13,10 +13,12 @@ func (j JsonTester) String() string {
 	return string(j.RawMessage)
 }
 
+// IsObject peeks at the first non-whitespace byte
 func (j JsonTester) IsObject() bool {
-	return strings.HasPrefix(string(j.RawMessage), "{")
+	return strings.HasPrefix(strings.TrimSpace(string(j.RawMessage)), "{")
 }
 
+// IsArray peeks at the first non-whitespace byte
 func (j JsonTester) IsArray() bool {
-	return strings.HasPrefix(string(j.RawMessage), "[")
+	return strings.HasPrefix(strings.TrimSpace(string(j.RawMessage)), "[")
 }
@@ -18,7 +18,7 @@ const (
 func (s *Service) VatCodesGet(requestParams *VatCodesGetParams, ctx context.Context) (*VatCodesGetResponse, error) {
 	method := http.MethodGet
 	responseBody := s.NewVatCodesGetResponse()
-	path := s.rest.SubPath(VatCodesEndpoint)
+	path := VatCodesEndpoint
 
 	// create a new HTTP request
 	httpReq, err := s.rest.NewRequest(ctx, method, path, nil)
@@ -0,0 +1,135 @@
+// Package webhook parses and verifies the webhook notifications Exact Online
+// sends for subscribed topics.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/tim-online/go-exactonline/edm"
+)
+
+const (
+	// maximum size of a notification body
+	maxBodySize = 1 << 20
+)
+
+var (
+	// Tolerance is how old a notification may be before it's rejected as a
+	// replay
+	Tolerance = 5 * time.Minute
+
+	ErrInvalidSignature = errors.New("Invalid webhook signature")
+	ErrExpired          = errors.New("Webhook notification is too old")
+)
+
+// Notification is the content of a webhook notification
+type Notification struct {
+	Topic               string   `json:"Topic"`
+	ClientID            edm.GUID `json:"ClientId"`
+	Division            int      `json:"Division"`
+	Action              string   `json:"Action"`
+	Key                 edm.GUID `json:"Key"`
+	ExactOnlineEndpoint string   `json:"ExactOnlineEndpoint"`
+	EventCreatedOn      string   `json:"EventCreatedOn"`
+}
+
+// CreatedOn returns the time the event was created
+func (n *Notification) CreatedOn() (time.Time, error) {
+	return parseTime(n.EventCreatedOn)
+}
+
+// payload is the body of a webhook request
+type payload struct {
+	Content  json.RawMessage `json:"Content"`
+	HashCode string          `json:"HashCode"`
+}
+
+// Parse reads the notification from the body of r and verifies it was signed
+// with secret. Notifications older than Tolerance are rejected.
+func Parse(r *http.Request, secret string) (*Notification, error) {
+	defer r.Body.Close()
+	return ParseBody(io.LimitReader(r.Body, maxBodySize), secret, time.Now())
+}
+
+// ParseBody works like Parse for a raw notification body, using now to check
+// the age of the notification
+func ParseBody(body io.Reader, secret string, now time.Time) (*Notification, error) {
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	p := payload{}
+	err = json.Unmarshal(b, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	if !Verify(p.Content, p.HashCode, secret) {
+		return nil, ErrInvalidSignature
+	}
+
+	n := &Notification{}
+	err = json.Unmarshal(p.Content, n)
+	if err != nil {
+		return nil, err
+	}
+
+	createdOn, err := n.CreatedOn()
+	if err != nil {
+		return nil, err
+	}
+
+	age := now.Sub(createdOn)
+	if age > Tolerance || age < -Tolerance {
+		return nil, ErrExpired
+	}
+
+	return n, nil
+}
+
+// Verify reports if hashCode is the HMAC-SHA256 of the raw content with secret
+func Verify(content []byte, hashCode string, secret string) bool {
+	expected, err := hex.DecodeString(hashCode)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(content)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+var dateNotation = regexp.MustCompile(`^/Date\((-?[0-9]+)\)/$`)
+
+// parseTime parses the EventCreatedOn notations: ISO 8601 with or without
+// timezone (UTC is assumed) and /Date(1488939627017)/
+func parseTime(value string) (time.Time, error) {
+	if m := dateNotation.FindStringSubmatch(value); m != nil {
+		milis, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.UnixMilli(milis).UTC(), nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid EventCreatedOn %q", value)
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+const secret = "webhook-secret"
+
+var now = time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func content(createdOn string) string {
+	return `{"Topic":"Accounts","ClientId":"5f3a8c4e-8e0f-4f62-9b8c-6f1d2e9a7b10","Division":123,` +
+		`"Action":"Update","Key":"bd1a2f33-5f57-4ed8-b8ff-3f1d09a8b6c7",` +
+		`"ExactOnlineEndpoint":"https://start.exactonline.nl/api/v1/123/crm/Accounts(guid'bd1a2f33-5f57-4ed8-b8ff-3f1d09a8b6c7')",` +
+		`"EventCreatedOn":"` + createdOn + `"}`
+}
+
+func sign(content string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(content))
+	return strings.ToUpper(hex.EncodeToString(mac.Sum(nil)))
+}
+
+func body(content string, hashCode string) string {
+	return `{"Content":` + content + `,"HashCode":"` + hashCode + `"}`
+}
+
+func TestParseBody(t *testing.T) {
+	c := content("2023-05-01T11:59:00")
+
+	for _, hashCode := range []string{sign(c, secret), strings.ToLower(sign(c, secret))} {
+		n, err := ParseBody(strings.NewReader(body(c, hashCode)), secret, now)
+		if err != nil {
+			t.Fatalf("ParseBody with hash %s returned error: %s", hashCode, err)
+		}
+
+		if n.Topic != "Accounts" || n.Division != 123 || n.Action != "Update" {
+			t.Errorf("ParseBody = %+v", n)
+		}
+		if n.Key.String() != "bd1a2f33-5f57-4ed8-b8ff-3f1d09a8b6c7" {
+			t.Errorf("ParseBody Key = %s", n.Key)
+		}
+	}
+}
+
+func TestParseBodyInvalidSignature(t *testing.T) {
+	c := content("2023-05-01T11:59:00")
+	tampered := strings.Replace(c, `"Division":123`, `"Division":124`, 1)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"tampered content", body(tampered, sign(c, secret))},
+		{"wrong secret", body(c, sign(c, "other-secret"))},
+		{"malformed hex", body(c, "not-hex")},
+		{"odd length hex", body(c, sign(c, secret)[1:])},
+		{"empty hash", body(c, "")},
+	}
+
+	for _, test := range tests {
+		_, err := ParseBody(strings.NewReader(test.body), secret, now)
+		if err != ErrInvalidSignature {
+			t.Errorf("%s: ParseBody returned %v, want ErrInvalidSignature", test.name, err)
+		}
+	}
+}
+
+func TestParseBodyReplay(t *testing.T) {
+	tests := []struct {
+		createdOn string
+		err       error
+	}{
+		{"2023-05-01T11:56:00Z", nil},
+		{"/Date(1682942340000)/", nil},
+		{"2023-05-01T12:04:00", nil},
+		{"2023-05-01T11:50:00", ErrExpired},
+		{"2023-05-01T12:10:00Z", ErrExpired},
+		{"/Date(1682935200000)/", ErrExpired},
+	}
+
+	for _, test := range tests {
+		c := content(test.createdOn)
+		_, err := ParseBody(strings.NewReader(body(c, sign(c, secret))), secret, now)
+		if err != test.err {
+			t.Errorf("EventCreatedOn %s: ParseBody returned %v, want %v", test.createdOn, err, test.err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	c := []byte(content("2023-05-01T11:59:00"))
+	hashCode := sign(string(c), secret)
+
+	if !Verify(c, hashCode, secret) {
+		t.Error("Verify rejected a valid uppercase signature")
+	}
+	if !Verify(c, strings.ToLower(hashCode), secret) {
+		t.Error("Verify rejected a valid lowercase signature")
+	}
+	if Verify(c, "zz"+hashCode[2:], secret) {
+		t.Error("Verify accepted malformed hex")
+	}
+	if Verify(c, hashCode, "") {
+		t.Error("Verify accepted a signature with the wrong secret")
+	}
+}